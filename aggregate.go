@@ -0,0 +1,166 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// fetchWorkers bounds how many feeds are downloaded at once.
+const fetchWorkers = 4
+
+// allTitle names the aggregated view of every category.
+const allTitle = "Tutte le notizie"
+
+// fetchResult is the outcome of fetching one category.
+type fetchResult struct {
+	category FeedCategory
+	rss      *Rss
+	err      error
+}
+
+// fetchAll fetches categories with a bounded pool of workers, each feed
+// with its own timeout. Results are in the order of categories.
+func (r *RssReader) fetchAll(ctx context.Context, categories []FeedCategory) []fetchResult {
+	results := make([]fetchResult, len(categories))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(fetchWorkers, len(categories)) {
+		wg.Go(func() {
+			for i := range jobs {
+				ctx, cancel := context.WithTimeout(ctx, r.timeout)
+				rss, err := r.fetchFeed(ctx, categories[i].URL)
+				cancel()
+				results[i] = fetchResult{category: categories[i], rss: rss, err: err}
+			}
+		})
+	}
+	for i := range categories {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// mergeFeeds merges the items of the fetched feeds, newest first, labelling
+// each with its category. An item listed in several categories is kept
+// once, under the first of them; undated items go last.
+func mergeFeeds(results []fetchResult) *Rss {
+	type dated struct {
+		item      Item
+		published time.Time
+	}
+
+	var pool []dated
+	seen := make(map[string]bool)
+	for _, res := range results {
+		if res.err != nil {
+			continue
+		}
+		for _, item := range res.rss.Channel.Items {
+			if item.Link != "" && seen[item.Link] {
+				continue
+			}
+			seen[item.Link] = true
+
+			item.Source = res.category.Name
+			published, _ := parsePubDate(item.PubDate)
+			pool = append(pool, dated{item, published})
+		}
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool {
+		if pool[j].published.IsZero() {
+			return !pool[i].published.IsZero()
+		}
+		return pool[i].published.After(pool[j].published)
+	})
+
+	// The categories share a publisher: take its link and copyright from
+	// the first feed that has them, for attribution.
+	rss := &Rss{Channel: Channel{Title: allTitle}}
+	for _, res := range results {
+		if res.err == nil && rss.Channel.Link == "" {
+			rss.Channel.Link = res.rss.Channel.Link
+		}
+		if res.err == nil && rss.Channel.Copyright == "" {
+			rss.Channel.Copyright = res.rss.Channel.Copyright
+		}
+	}
+	for _, d := range pool {
+		rss.Channel.Items = append(rss.Channel.Items, d.item)
+	}
+	return rss
+}
+
+// reportFailures reports the categories that failed to load and returns
+// how many were fetched. A category still hidden for failing was only
+// retried, and fails quietly.
+func (r *RssReader) reportFailures(results []fetchResult) int {
+	states, _ := loadFeedStates()
+	now := time.Now()
+	fetched := 0
+	for _, res := range results {
+		if res.err != nil {
+			if states != nil && r.hidden(res.category, states, now) {
+				continue
+			}
+			fmt.Fprintf(r.out.diag, "%s>> Errore nel scaricare %s: %v%s\n", ColorRed, res.category.Name, res.err, ColorReset)
+			continue
+		}
+		fetched++
+	}
+	return fetched
+}
+
+// fetchEverything fetches and merges every category not hidden for
+// failing, and the hidden ones due for a retry, reporting the ones that
+// fail, and returns how many were fetched.
+func (r *RssReader) fetchEverything() (*Rss, int) {
+	results := r.fetchAll(context.Background(), r.aggregateCategories())
+	fetched := r.reportFailures(results)
+	return mergeFeeds(results), fetched
+}
+
+// selectAll shows the aggregated view, making it the current feed.
+func (r *RssReader) selectAll() {
+	fmt.Fprintln(r.out.diag, "Caricamento di tutte le categorie in corso...")
+
+	start := time.Now()
+	rss, fetched := r.fetchEverything()
+	elapsed := time.Since(start)
+	if fetched == 0 {
+		return
+	}
+
+	r.current = rss
+	r.currentFeed = FeedCategory{Name: allTitle}
+	r.tagFilter = ""
+	r.applyCategoryPrefs(r.currentFeed)
+	r.displayFeed(rss)
+	shown, _ := r.countVisible(rss)
+	r.stats.feedsFetched += fetched
+	r.stats.itemsShown += shown
+	if len(rss.Channel.Items) > 0 {
+		r.displayFooter(rss, elapsed)
+	}
+}
+
+// sourceOf returns the category item comes from: the one it was merged
+// from in the aggregated view, the current feed otherwise.
+func (r *RssReader) sourceOf(item Item) FeedCategory {
+	if item.Source != "" {
+		if cat, ok := r.findCategory(item.Source); ok {
+			return cat
+		}
+	}
+	return r.currentFeed
+}
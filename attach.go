@@ -0,0 +1,168 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// attachClient talks to the API of a running "adncli serve", which then
+// fetches the feeds and keeps the read-later queue for every terminal
+// attached to it.
+type attachClient struct {
+	fetcher Fetcher
+	base    *url.URL
+	token   string
+}
+
+// do sends a request to the API path of version apiVersion and decodes
+// the data of the response into out, if not nil. It returns the status.
+func (a *attachClient) do(ctx context.Context, method, path string, body, out any) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	path, query, _ := strings.Cut(path, "?")
+	u := a.base.JoinPath("v"+strconv.Itoa(apiVersion), path)
+	u.User = nil
+	u.RawQuery = query
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case a.token != "":
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	case a.base.User != nil:
+		password, _ := a.base.User.Password()
+		req.SetBasicAuth(a.base.User.Username(), password)
+	}
+
+	resp, err := a.fetcher.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		SchemaVersion int             `json:"schema_version"`
+		Data          json.RawMessage `json:"data"`
+		Error         string          `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return resp.StatusCode, fmt.Errorf("adncli serve: risposta non valida (%s): %w", resp.Status, err)
+	}
+	if envelope.Error != "" {
+		return resp.StatusCode, fmt.Errorf("adncli serve: %s", envelope.Error)
+	}
+	if envelope.SchemaVersion != apiVersion {
+		return resp.StatusCode, fmt.Errorf("adncli serve: versione dello schema %d inattesa", envelope.SchemaVersion)
+	}
+	if out != nil {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("adncli serve: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// attach makes the reader a client of the adncli serve at rawURL: the
+// menu lists the categories of the server, which fetches every feed.
+func (r *RssReader) attach(rawURL, token string) error {
+	base, err := url.Parse(rawURL)
+	if err != nil || base.Host == "" {
+		return fmt.Errorf("indirizzo %q non valido per --attach", rawURL)
+	}
+	a := &attachClient{fetcher: r.fetcher, base: base, token: token}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	var cats []apiCategory
+	if _, err := a.do(ctx, http.MethodGet, "categories", nil, &cats); err != nil {
+		return err
+	}
+
+	r.categories = nil
+	for _, c := range cats {
+		r.categories = append(r.categories, FeedCategory{ID: c.ID, Name: c.Name, URL: c.URL})
+	}
+	r.attached = a
+	return nil
+}
+
+// attachedFeed asks the server for the feed of the category at url and
+// converts it back to the RSS model.
+func (r *RssReader) attachedFeed(ctx context.Context, url string) (*Rss, error) {
+	id := 0
+	for _, cat := range r.categories {
+		if cat.URL == url {
+			id = cat.ID
+		}
+	}
+	if id == 0 {
+		return nil, fmt.Errorf("adncli serve: nessuna categoria per %s", url)
+	}
+
+	var feed apiFeed
+	if _, err := r.attached.do(ctx, http.MethodGet, "feeds/"+strconv.Itoa(id), nil, &feed); err != nil {
+		return nil, err
+	}
+	rss := &Rss{Channel: Channel{Title: feed.Title}}
+	for _, it := range feed.Items {
+		item := Item{Title: it.Title, Link: it.Link, Description: it.Description, Categories: it.Tags}
+		if !it.Published.IsZero() {
+			item.PubDate = it.Published.Format(time.RFC1123Z)
+		}
+		rss.Channel.Items = append(rss.Channel.Items, item)
+	}
+	return rss, nil
+}
+
+// attachedQueue returns the read-later queue kept by the server.
+func (r *RssReader) attachedQueue() (*readQueue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	q := &readQueue{}
+	if _, err := r.attached.do(ctx, http.MethodGet, "queue", nil, &q.Items); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// attachedEnqueue adds item to the queue of the server, reporting false if
+// it was already queued.
+func (r *RssReader) attachedEnqueue(item queuedItem) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	body := apiQueued{Title: item.Title, Link: item.Link, Category: item.Category}
+	status, err := r.attached.do(ctx, http.MethodPost, "queue", body, nil)
+	return status == http.StatusCreated, err
+}
+
+// attachedDequeue takes the item with the given link off the queue of the
+// server.
+func (r *RssReader) attachedDequeue(link string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	_, err := r.attached.do(ctx, http.MethodDelete, "queue?link="+url.QueryEscape(link), nil, nil)
+	return err
+}
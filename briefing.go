@@ -0,0 +1,78 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// readingWordsPerMinute is the reading speed used to size a briefing.
+const readingWordsPerMinute = 200
+
+// briefingItem is an item picked for a briefing, with its source category.
+type briefingItem struct {
+	Item
+	category  string
+	published time.Time
+}
+
+// readingTime estimates how long it takes to read the item's title and,
+// unless hidden, its cleaned description.
+func (r *RssReader) readingTime(item Item) time.Duration {
+	words := len(strings.Fields(item.Title))
+	if !r.hideDesc {
+		words += len(strings.Fields(r.cleanText(r.sourceOf(item), item.Description)))
+	}
+	return time.Duration(words) * time.Minute / readingWordsPerMinute
+}
+
+// Briefing fetches the given categories concurrently and prints the
+// freshest items that fit in the reading budget.
+func (r *RssReader) Briefing(budget time.Duration, categories []FeedCategory) {
+	results := r.fetchAll(context.Background(), categories)
+	r.reportFailures(results)
+
+	var pool []briefingItem
+	for _, item := range mergeFeeds(results).Channel.Items {
+		published, _ := parsePubDate(item.PubDate)
+		pool = append(pool, briefingItem{Item: item, category: item.Source, published: published})
+	}
+
+	var picked []briefingItem
+	var total time.Duration
+	for _, item := range pool {
+		cost := r.readingTime(item.Item)
+		if total+cost > budget {
+			break
+		}
+		total += cost
+		picked = append(picked, item)
+	}
+
+	fmt.Fprintf(r.out.content, "\n%s=== BRIEFING (%s) ===%s\n", ColorBold+ColorGreen, budget, ColorReset)
+	if len(picked) == 0 {
+		fmt.Fprintln(r.out.content, "Nessuna notizia rientra nel tempo indicato.")
+		return
+	}
+
+	for _, item := range picked {
+		when := "--:--"
+		if !item.published.IsZero() {
+			when = item.published.Local().Format("15:04")
+		}
+		fmt.Fprintf(r.out.content, "%s%s%s %s[%s]%s %s%s%s\n", ColorCyan, when, ColorReset,
+			ColorYellow, item.category, ColorReset,
+			ColorBold, strings.TrimSpace(item.Title), ColorReset)
+		if desc := r.cleanText(r.sourceOf(item.Item), item.Description); desc != "" && !r.hideDesc {
+			fmt.Fprintf(r.out.content, "      %s\n", desc)
+		}
+	}
+
+	fmt.Fprintf(r.out.content, "\n%s%d notizie, circa %s di lettura%s\n", ColorPurple, len(picked),
+		total.Round(time.Second), ColorReset)
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// defaultCleanStages is the pipeline applied to descriptions unless
+// overridden with --clean.
+var defaultCleanStages = []string{"alt", "tags", "entities", "spaces", "boilerplate"}
+
+// htmlTag matches an HTML or XML tag, for the "tags" stage and for
+// titles, which are not cleaned.
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+var (
+	imgRegex        = regexp.MustCompile(`(?is)<img\b[^>]*>`)
+	altRegex        = regexp.MustCompile(`(?is)\balt\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	figcaptionRegex = regexp.MustCompile(`(?is)<figcaption\b[^>]*>(.*?)</figcaption>`)
+)
+
+// imageText replaces images with their alt text and figure captions with
+// their text, as "[Immagine: ...]", so that what they convey survives
+// the removal of tags. Images without alt text are decorative and dropped.
+func imageText(s string) string {
+	s = imgRegex.ReplaceAllStringFunc(s, func(tag string) string {
+		m := altRegex.FindStringSubmatch(tag)
+		if m == nil {
+			return ""
+		}
+		alt := strings.TrimSpace(m[1] + m[2])
+		if alt == "" {
+			return ""
+		}
+		return " [Immagine: " + alt + "] "
+	})
+	return figcaptionRegex.ReplaceAllStringFunc(s, func(fig string) string {
+		caption := strings.TrimSpace(figcaptionRegex.FindStringSubmatch(fig)[1])
+		if caption == "" {
+			return ""
+		}
+		return " [Immagine: " + caption + "] "
+	})
+}
+
+// defaultBoilerplate matches the calls to action publishers append to
+// descriptions.
+var defaultBoilerplate = []string{
+	`(?i)\s*(continua a leggere|leggi tutto|leggi l'articolo)[\s.…»>]*$`,
+}
+
+// textStage is a named step of the text-cleaning pipeline.
+type textStage struct {
+	name  string
+	apply func(string) string
+}
+
+// compileBoilerplate compiles boilerplate rules, regular expressions
+// whose matches are removed from descriptions.
+func compileBoilerplate(patterns []string) ([]*regexp.Regexp, error) {
+	var rules []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boilerplate rule %q: %w", pattern, err)
+		}
+		rules = append(rules, re)
+	}
+	return rules, nil
+}
+
+// removeAll deletes the matches of every rule from s.
+func removeAll(s string, rules []*regexp.Regexp) string {
+	for _, re := range rules {
+		s = re.ReplaceAllString(s, "")
+	}
+	return s
+}
+
+// setCleanPipeline builds the stages used by cleanText, in the given order.
+// Boilerplate patterns are regular expressions whose matches are removed by
+// the "boilerplate" stage.
+func (r *RssReader) setCleanPipeline(stages, boilerplate []string) error {
+	rules, err := compileBoilerplate(boilerplate)
+	if err != nil {
+		return err
+	}
+
+	spaces, err := regexp.Compile(`\s+`)
+	if err != nil {
+		return fmt.Errorf("failed to compile regex: %w", err)
+	}
+
+	available := map[string]func(string) string{
+		"alt": imageText,
+		"tags": func(s string) string {
+			return r.htmlTagRegex.ReplaceAllString(s, "")
+		},
+		"entities": html.UnescapeString,
+		"spaces": func(s string) string {
+			return spaces.ReplaceAllString(s, " ")
+		},
+		"boilerplate": func(s string) string {
+			return removeAll(s, rules)
+		},
+	}
+
+	// An empty --clean leaves descriptions as the feed sends them.
+	var pipeline []textStage
+	for _, name := range stages {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		apply, ok := available[name]
+		if !ok {
+			return fmt.Errorf("unknown cleaning stage %q", name)
+		}
+		pipeline = append(pipeline, textStage{name: name, apply: apply})
+	}
+
+	r.pipeline = pipeline
+	return nil
+}
+
+// setCategoryBoilerplate gives cat a pipeline of its own, whose
+// "boilerplate" stage also removes the matches of patterns. Without that
+// stage in the pipeline the rules have no effect, like the general ones.
+func (r *RssReader) setCategoryBoilerplate(cat FeedCategory, patterns []string) error {
+	rules, err := compileBoilerplate(patterns)
+	if err != nil {
+		return fmt.Errorf("categoria %q: %w", cat.Name, err)
+	}
+	pipeline := slices.Clone(r.pipeline)
+	for i, stage := range pipeline {
+		if stage.name == "boilerplate" {
+			general := stage.apply
+			pipeline[i].apply = func(s string) string {
+				return removeAll(general(s), rules)
+			}
+		}
+	}
+	if r.categoryPipelines == nil {
+		r.categoryPipelines = make(map[int][]textStage)
+	}
+	r.categoryPipelines[cat.ID] = pipeline
+	return nil
+}
+
+// pipelineFor returns the cleaning stages for descriptions of cat.
+func (r *RssReader) pipelineFor(cat FeedCategory) []textStage {
+	if pipeline, ok := r.categoryPipelines[cat.ID]; ok {
+		return pipeline
+	}
+	return r.pipeline
+}
+
+// cleanText runs a description of cat through its cleaning pipeline.
+func (r *RssReader) cleanText(cat FeedCategory, text string) string {
+	for _, stage := range r.pipelineFor(cat) {
+		text = stage.apply(text)
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestCategoryBoilerplate(t *testing.T) {
+	r := &RssReader{htmlTagRegex: htmlTag}
+	if err := r.setCleanPipeline(defaultCleanStages, defaultBoilerplate); err != nil {
+		t.Fatal(err)
+	}
+	sport := FeedCategory{ID: 1, Name: "Sport"}
+	esteri := FeedCategory{ID: 2, Name: "Esteri"}
+	if err := r.setCategoryBoilerplate(sport, []string{`\s*Segui la diretta.*$`}); err != nil {
+		t.Fatal(err)
+	}
+
+	desc := "<p>Vince la Roma.</p> Segui la diretta su ANSA. Continua a leggere"
+	if got, want := r.cleanText(sport, desc), "Vince la Roma."; got != want {
+		t.Errorf("Sport: got %q, want %q", got, want)
+	}
+	if got, want := r.cleanText(esteri, desc), "Vince la Roma. Segui la diretta su ANSA."; got != want {
+		t.Errorf("Esteri: got %q, want %q", got, want)
+	}
+	if err := r.setCategoryBoilerplate(sport, []string{"("}); err == nil {
+		t.Error("invalid rule accepted")
+	}
+}
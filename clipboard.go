@@ -0,0 +1,67 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// defaultLinkTemplate renders an item as a Markdown link.
+const defaultLinkTemplate = "[{title}]({link})"
+
+// errNoClipboard is returned when no clipboard tool is installed.
+var errNoClipboard = errors.New("no clipboard tool found")
+
+// clipboardCommand returns the command writing its stdin to the system
+// clipboard, trying the usual tool of each platform in turn.
+func clipboardCommand() (*exec.Cmd, error) {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"})
+	}
+
+	for _, c := range candidates {
+		if path, err := exec.LookPath(c[0]); err == nil {
+			return exec.Command(path, c[1:]...), nil
+		}
+	}
+	return nil, errNoClipboard
+}
+
+// copyToClipboard places text on the system clipboard.
+func copyToClipboard(text string) error {
+	cmd, err := clipboardCommand()
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// itemLink renders item with the link template. {title}, {link} and
+// {category} are replaced; brackets in the title are escaped so that it
+// cannot end a Markdown link text early.
+func (r *RssReader) itemLink(item *Item) string {
+	title := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(strings.TrimSpace(item.Title))
+	return strings.NewReplacer(
+		"{title}", title,
+		"{link}", item.Link,
+		"{category}", r.sourceOf(*item).Name,
+	).Replace(r.linkTemplate)
+}
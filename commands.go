@@ -0,0 +1,232 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// command is an adncli subcommand. Without one, adncli starts the
+// interactive menu.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(r *RssReader, args []string) error
+}
+
+// commands lists the subcommands in the order shown by --help.
+var commands = []command{
+	{"list", "list [--all]", "elenca le categorie", (*RssReader).runList},
+	{"feed", "feed add <nome> <url> | feed list [--all]", "aggiunge un feed personale al menu o li elenca", (*RssReader).runFeed},
+	{"fetch", "fetch [--limit N] [--new-only] <categoria>... | all", "stampa le notizie delle categorie, o di tutte insieme", (*RssReader).runFetch},
+	{"search", "search [--category C] <termine>", "cerca un termine nelle notizie di tutte le categorie", (*RssReader).runSearch},
+	{"archive", "archive [--category C] [--limit N] [termine] | archive prune [--vacuum]", "sfoglia e cerca le notizie archiviate, o ne rimuove le più vecchie", (*RssReader).runArchive},
+	{"serve", "serve [--addr host:porta] [--read-only]", "espone categorie, notizie e coda come API JSON", (*RssReader).runServe},
+	{"run", "run <manifest.yaml>", "esegue i task di un manifest YAML", (*RssReader).runManifestCommand},
+	{"queue", "queue [open N | clear]", "mostra o gestisce la coda di lettura", (*RssReader).runQueue},
+	{"history", "history [--since 7d] [--category C] [--opened] [termine]", "elenca le notizie lette o scaricate di recente", (*RssReader).runHistory},
+	{"starred", "starred [open N | remove N]", "elenca o gestisce le notizie salvate", (*RssReader).runStarred},
+	{"stats", "stats --opened", "riepiloga le notizie aperte", (*RssReader).runStats},
+	{"statusline", "statusline [--category C]", "stampa l'ultimo titolo per la barra di tmux", (*RssReader).runStatusline},
+}
+
+// commandAliases maps alternative spellings to subcommand names.
+var commandAliases = map[string]string{"feeds": "feed"}
+
+// findCommand returns the subcommand called name.
+func findCommand(name string) (*command, bool) {
+	if alias, ok := commandAliases[name]; ok {
+		name = alias
+	}
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i], true
+		}
+	}
+	return nil, false
+}
+
+// subcommandNames returns the subcommand names, for suggestions.
+func subcommandNames() []string {
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = c.name
+	}
+	return names
+}
+
+// parseInterspersed parses args with fs, accepting flags after positional
+// arguments as in "adncli fetch esteri --limit 10", and returns the
+// positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := parseFlags(fs, args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// plainIfPiped drops colors when stdout is not a terminal, to keep escape
+// codes out of pipes and files.
+func plainIfPiped() {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		disableColors()
+	}
+}
+
+// parseFlags parses args with fs. Errors other than -h become usage
+// errors; the flag package has already printed them with the usage of fs.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return &usageError{usage: fs.Name(), err: err}
+	}
+	return err
+}
+
+// exitCode reports err on stderr and returns the matching exit status:
+// 0 after -h, 2 for usage errors, 1 for any other failure. Help and flag
+// errors were already printed by the flag package.
+func exitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	var usage *usageError
+	if errors.As(err, &usage) {
+		if usage.err == nil {
+			fmt.Fprintf(os.Stderr, "%sErrore: %v%s\n", ColorRed, err, ColorReset)
+		}
+		return 2
+	}
+	fmt.Fprintf(os.Stderr, "%sErrore: %v%s\n", ColorRed, err, ColorReset)
+	return 1
+}
+
+// runList implements "adncli list [--all]". Categories hidden for failing
+// are only listed with --all, marked as such.
+func (r *RssReader) runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	all := fs.Bool("all", false, "elenca anche le categorie nascoste perché non funzionano")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return &usageError{usage: "adncli list [--all]"}
+	}
+
+	states, err := loadFeedStates()
+	if err != nil {
+		return err
+	}
+
+	plainIfPiped()
+	now := time.Now()
+	hidden := 0
+	for _, cat := range r.categories {
+		mark := ""
+		if r.hidden(cat, states, now) {
+			if !*all {
+				hidden++
+				continue
+			}
+			days := int(states.Feeds[cat.URL].failingFor(now).Hours() / 24)
+			mark = fmt.Sprintf("  %s(nascosta: non funziona da %d giorni)%s", ColorRed, days, ColorReset)
+		}
+		fmt.Fprintf(r.out.content, "%s%2d%s  %s  %s%s\n", ColorYellow, cat.ID, ColorReset, pad(cat.Name, 14), cat.URL, mark)
+	}
+	if hidden > 0 {
+		fmt.Fprintf(r.out.diag, "%d categorie nascoste perché non funzionano; usa --all per elencarle.\n", hidden)
+	}
+	return nil
+}
+
+// runFetch implements "adncli fetch [--limit N] [--new-only] <categoria>...".
+func (r *RssReader) runFetch(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	limit := fs.Int("limit", 0, "numero massimo di notizie per categoria (0: tutte)")
+	newOnly := fs.Bool("new-only", false, "mostra solo le notizie apparse dall'ultimo adncli fetch")
+	keys, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 || *limit < 0 {
+		return &usageError{usage: "adncli fetch [--limit N] [--new-only] <categoria>... | all"}
+	}
+	return r.printFeeds(keys, *limit, *newOnly)
+}
+
+// printFeeds prints the feeds of the given categories, at most limit items
+// each, without the menu. The single key "all" prints the aggregated view
+// of every category. The items found are recorded, and with newOnly only
+// those missing from the previous record are printed.
+func (r *RssReader) printFeeds(keys []string, limit int, newOnly bool) error {
+	seen, err := loadSeen()
+	if err != nil {
+		return err
+	}
+	defer seen.save()
+
+	if len(keys) == 1 && strings.EqualFold(keys[0], "all") {
+		return r.printAll(limit, seen, newOnly)
+	}
+
+	task := manifestTask{Limit: limit, Output: r.output, NoDesc: r.hideDesc, categoryPrefs: true, seen: seen, newOnly: newOnly}
+	for _, key := range keys {
+		if _, ok := r.findCategory(key); !ok {
+			return fmt.Errorf("categoria %q sconosciuta", strings.TrimSpace(key))
+		}
+		task.Categories = append(task.Categories, key)
+	}
+
+	plainIfPiped()
+	return r.runTask(task)
+}
+
+// printAll prints the aggregated view for printFeeds, leaving out the
+// categories hidden for failing as the menu does, unless due for a retry.
+func (r *RssReader) printAll(limit int, seen *seenItems, newOnly bool) error {
+	plainIfPiped()
+	results := r.fetchAll(context.Background(), r.aggregateCategories())
+	if r.reportFailures(results) == 0 {
+		return errors.New("nessuna categoria scaricata")
+	}
+	for _, res := range results {
+		if res.err != nil {
+			continue
+		}
+		all := res.rss.Channel.Items
+		if newOnly {
+			res.rss.Channel.Items = seen.unseen(res.category.URL, all)
+		}
+		seen.record(res.category.URL, all)
+	}
+
+	rss := mergeFeeds(results)
+	if limit > 0 && len(rss.Channel.Items) > limit {
+		rss.Channel.Items = rss.Channel.Items[:limit]
+	}
+	if newOnly && len(rss.Channel.Items) == 0 {
+		fmt.Fprintln(r.out.content, "Nessuna notizia nuova dall'ultimo controllo.")
+		return nil
+	}
+	r.currentFeed = FeedCategory{Name: allTitle}
+	r.displayFeed(rss)
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	parse := func(args ...string) error {
+		fs := flag.NewFlagSet("list", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Bool("all", false, "")
+		return parseFlags(fs, args)
+	}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, 0},
+		{"help", parse("-h"), 0},
+		{"unknown flag", parse("--bogus"), 2},
+		{"bad value", parse("--all=forse"), 2},
+		{"missing argument", &usageError{usage: "adncli fetch <categoria>"}, 2},
+		{"failure", errors.New("rete non raggiungibile"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheDir returns the directory holding the last body of each feed,
+// $XDG_CACHE_HOME/adncli/feeds or ~/.cache/adncli/feeds, or a cache
+// folder inside the data directory when that is configured explicitly.
+func cacheDir() (string, error) {
+	var base string
+	switch {
+	case dataHome != "":
+		base = filepath.Join(dataHome, "cache")
+	case os.Getenv("XDG_CACHE_HOME") != "":
+		base = filepath.Join(os.Getenv("XDG_CACHE_HOME"), "adncli")
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot locate home directory: %w", err)
+		}
+		base = filepath.Join(home, ".cache", "adncli")
+	}
+
+	dir := filepath.Join(base, "feeds")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// cachedBodyPath returns where the last body of url is kept.
+func cachedBodyPath(url string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("%x", sha256.Sum256([]byte(url)))), nil
+}
+
+// validators are the headers that let a server answer 304 Not Modified.
+type validators struct {
+	etag, lastModified string
+}
+
+// savedValidators returns the validators stored for url, provided its
+// body is still cached: without the body a 304 would be useless.
+func savedValidators(url string) validators {
+	path, err := cachedBodyPath(url)
+	if err != nil {
+		return validators{}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return validators{}
+	}
+	states, err := loadFeedStates()
+	if err != nil {
+		return validators{}
+	}
+	st, ok := states.Feeds[url]
+	if !ok {
+		return validators{}
+	}
+	return validators{st.ETag, st.LastModified}
+}
+
+// saveValidators stores the validators of url.
+func saveValidators(url string, v validators) {
+	updateFeedState(url, func(st *feedStatus) {
+		st.ETag, st.LastModified = v.etag, v.lastModified
+	})
+}
+
+// saveFreshUntil stores until when the cached body of url may be served
+// without a request.
+func saveFreshUntil(url string, t time.Time) {
+	updateFeedState(url, func(st *feedStatus) {
+		st.FreshUntil = t
+	})
+}
+
+// forgetValidators drops the cached body and validators of url, so that
+// the next fetch is unconditional.
+func forgetValidators(url string) {
+	if path, err := cachedBodyPath(url); err == nil {
+		os.Remove(path)
+	}
+	saveValidators(url, validators{})
+	saveFreshUntil(url, time.Time{})
+}
+
+// freshness returns how long a response may be reused without asking the
+// server again: its Cache-Control max-age less its Age, or ttl if longer.
+// no-cache and no-store leave only ttl, which the user chose.
+func freshness(h http.Header, ttl time.Duration) time.Duration {
+	var maxAge time.Duration
+	for _, directive := range strings.Split(strings.Join(h.Values("Cache-Control"), ","), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-cache", "no-store":
+			return ttl
+		case "max-age":
+			if secs, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && secs > 0 {
+				maxAge = time.Duration(secs) * time.Second
+			}
+		}
+	}
+	if age, err := strconv.Atoi(h.Get("Age")); err == nil && age > 0 {
+		maxAge -= time.Duration(age) * time.Second
+	}
+	return max(maxAge, ttl)
+}
+
+// decodeCached returns the cached body of url, stored with validators v,
+// from memory or from disk.
+func (r *RssReader) decodeCached(url string, v validators) (*Rss, error) {
+	if rss, ok := r.recallParsed(url, v); ok {
+		return rss, nil
+	}
+	path, err := cachedBodyPath(url)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errBadCache, err)
+	}
+	defer f.Close()
+	rss, err := decodeFeed(f, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errBadCache, err)
+	}
+	r.rememberParsed(url, v, rss)
+	return rss, nil
+}
+
+// freshCached returns the cached body of url if it is still fresh.
+func (r *RssReader) freshCached(url string) (*Rss, bool) {
+	states, err := loadFeedStates()
+	if err != nil {
+		return nil, false
+	}
+	st, ok := states.Feeds[url]
+	if !ok || !time.Now().Before(st.FreshUntil) {
+		return nil, false
+	}
+	rss, err := r.decodeCached(url, validators{st.ETag, st.LastModified})
+	return rss, err == nil
+}
+
+// parsedFeed is a decoded feed kept in memory with the validators it was
+// served with, so that a 304 for the same validators needs no decoding.
+type parsedFeed struct {
+	v   validators
+	rss *Rss
+}
+
+// copyRss returns a copy of rss whose items can be trimmed or relabelled
+// without affecting the original.
+func copyRss(rss *Rss) *Rss {
+	c := *rss
+	c.Channel.Items = slices.Clone(rss.Channel.Items)
+	return &c
+}
+
+// rememberParsed keeps a copy of rss as the decoded body of url for
+// validators v.
+func (r *RssReader) rememberParsed(url string, v validators, rss *Rss) {
+	r.parsedMu.Lock()
+	defer r.parsedMu.Unlock()
+	if r.parsed == nil {
+		r.parsed = make(map[string]parsedFeed)
+	}
+	r.parsed[url] = parsedFeed{v, copyRss(rss)}
+}
+
+// recallParsed returns a copy of the decoded body of url, if the one in
+// memory was served with validators v.
+func (r *RssReader) recallParsed(url string, v validators) (*Rss, bool) {
+	r.parsedMu.Lock()
+	defer r.parsedMu.Unlock()
+	p, ok := r.parsed[url]
+	if !ok || p.v != v {
+		return nil, false
+	}
+	return copyRss(p.rss), true
+}
+
+// cacheWriter tees a response body into a temporary file that replaces
+// the cached body of a feed once the whole response has been read.
+type cacheWriter struct {
+	f    *os.File
+	path string
+}
+
+// newCacheWriter starts caching a new body for url. It returns nil if the
+// cache is not writable, in which case the body is simply not cached.
+func newCacheWriter(url string) *cacheWriter {
+	path, err := cachedBodyPath(url)
+	if err != nil {
+		return nil
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".body.tmp*")
+	if err != nil {
+		return nil
+	}
+	return &cacheWriter{f: f, path: path}
+}
+
+// tee returns body, copying what is read from it to the cache.
+func (c *cacheWriter) tee(body io.Reader) io.Reader {
+	if c == nil {
+		return body
+	}
+	return io.TeeReader(body, c.f)
+}
+
+// commit reads what the decoder left of body into the cache and installs
+// it, or discards it when failed is set.
+func (c *cacheWriter) commit(body io.Reader, failed bool) error {
+	if c == nil {
+		return nil
+	}
+	defer os.Remove(c.f.Name())
+
+	if failed {
+		c.f.Close()
+		return nil
+	}
+	if _, err := io.Copy(c.f, body); err != nil {
+		c.f.Close()
+		return err
+	}
+	// Sync before the rename, or a crash can leave an empty cache file
+	// behind validators that keep the server from sending the body again.
+	if err := c.f.Sync(); err != nil {
+		c.f.Close()
+		return err
+	}
+	if err := c.f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(c.f.Name(), c.path)
+}
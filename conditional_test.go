@@ -0,0 +1,38 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFreshness(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		ttl    time.Duration
+		want   time.Duration
+	}{
+		{"no header", http.Header{}, 0, 0},
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=300"}}, 0, 5 * time.Minute},
+		{"quoted max-age", http.Header{"Cache-Control": {`max-age="60"`}}, 0, time.Minute},
+		{"age is subtracted", http.Header{"Cache-Control": {"max-age=300"}, "Age": {"100"}}, 0, 200 * time.Second},
+		{"stale", http.Header{"Cache-Control": {"max-age=60"}, "Age": {"100"}}, 0, 0},
+		{"ttl when longer", http.Header{"Cache-Control": {"max-age=60"}}, 10 * time.Minute, 10 * time.Minute},
+		{"split headers", http.Header{"Cache-Control": {"public", "MAX-AGE=120"}}, 0, 2 * time.Minute},
+		{"no-cache", http.Header{"Cache-Control": {"max-age=300, no-cache"}}, 0, 0},
+		{"no-store keeps ttl", http.Header{"Cache-Control": {"no-store, max-age=300"}}, time.Minute, time.Minute},
+		{"bad max-age", http.Header{"Cache-Control": {"max-age=soon"}}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := freshness(tt.header, tt.ttl); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
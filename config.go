@@ -0,0 +1,416 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// envPrefix starts the environment variables overriding settings; the rest
+// of the name is the flag name in upper case with dashes turned into
+// underscores, so --sort-menu becomes ADNCLI_SORT_MENU.
+const envPrefix = "ADNCLI_"
+
+// Config holds every user setting. Values are resolved in layers, each
+// overriding the previous one: built-in defaults, the config file,
+// ADNCLI_* environment variables, command-line flags.
+type Config struct {
+	// Timeout bounds a whole fetch; the others bound its phases, so that
+	// a dead host fails fast while a slow feed can still finish.
+	Timeout       time.Duration `toml:"timeout"`
+	DialTimeout   time.Duration `toml:"dial_timeout"`
+	TLSTimeout    time.Duration `toml:"tls_timeout"`
+	HeaderTimeout time.Duration `toml:"header_timeout"`
+
+	// CacheTTL is how long a fetched feed is reused without a request,
+	// at least; a longer Cache-Control max-age from the server wins.
+	CacheTTL time.Duration `toml:"cache_ttl"`
+
+	Output       string     `toml:"output"`
+	SortMenu     string     `toml:"sort_menu"`
+	Width        int        `toml:"width"`
+	NoDesc       bool       `toml:"no_desc"`
+	ScreenReader bool       `toml:"screen_reader"`
+	Clean        string     `toml:"clean"`
+	NoAlt        bool       `toml:"no_alt"`
+	Boilerplate  stringList `toml:"boilerplate"`
+	Proxy        string     `toml:"proxy"`
+	DataDir      string     `toml:"data_dir"`
+	SSHTunnel    string     `toml:"ssh_tunnel"`
+	Warmup       bool       `toml:"warmup"`
+	Probe        bool       `toml:"probe"`
+	NoStats      bool       `toml:"no_stats"`
+	Verbose      bool       `toml:"verbose"`
+	LinkTemplate string     `toml:"link_template"`
+	Locale       string     `toml:"locale"`
+	Attribution  string     `toml:"attribution"`
+
+	// ArchiveDays and ArchiveItems bound the item archive: older items,
+	// and the oldest beyond the count, are pruned. Zero means no bound.
+	ArchiveDays  int `toml:"archive_days"`
+	ArchiveItems int `toml:"archive_max_items"`
+
+	// HideFailingDays hides from the menu the categories whose feed has
+	// been failing for longer; zero keeps them all.
+	HideFailingDays int `toml:"hide_failing_days"`
+
+	// Attach is the address of an adncli serve to use instead of
+	// fetching feeds directly, and AttachToken its bearer token.
+	Attach      string `toml:"attach"`
+	AttachToken string `toml:"attach_token"`
+
+	// Resume reopens the category viewed last when the menu starts.
+	Resume bool `toml:"resume"`
+
+	// DefaultCategory is loaded as soon as the menu starts, and Feeds
+	// are added to the menu after the Adnkronos categories. Both can
+	// only be set in the config file.
+	DefaultCategory string       `toml:"default_category"`
+	Feeds           []feedConfig `toml:"feeds"`
+
+	// Categories holds display preferences for single categories, keyed
+	// by name or number, overriding Output and NoDesc when they are shown.
+	Categories map[string]categoryPrefs `toml:"categories"`
+
+	// The remaining settings select a one-off mode, so they are not read
+	// from the config file.
+	Briefing           time.Duration `toml:"-"`
+	BriefingCategories string        `toml:"-"`
+
+	// Category, when set, prints that feed and exits instead of
+	// starting the menu; Limit caps the items printed.
+	Category string `toml:"-"`
+	Limit    int    `toml:"-"`
+}
+
+// categoryPrefs are the preferences of one category, as in
+//
+//	[categories.Sport]
+//	no_desc = true
+//	mirrors = ["https://mirror.example.org/RSS_Sport.xml"]
+//	boilerplate = ['\s*Segui la diretta.*$']
+//
+// Unset fields keep the general setting. Mirrors are tried in order when
+// the feed cannot be fetched; boilerplate rules are removed from the
+// descriptions of the category after the general ones.
+type categoryPrefs struct {
+	Output      string   `toml:"output"`
+	NoDesc      *bool    `toml:"no_desc"`
+	Mirrors     []string `toml:"mirrors"`
+	Boilerplate []string `toml:"boilerplate"`
+}
+
+// setCategoryPrefs resolves the category keys of prefs, validating them.
+func (r *RssReader) setCategoryPrefs(prefs map[string]categoryPrefs) error {
+	r.prefs = make(map[int]categoryPrefs, len(prefs))
+	for key, p := range prefs {
+		cat, ok := r.findCategory(key)
+		if !ok {
+			return fmt.Errorf("preferenze per la categoria %q sconosciuta", key)
+		}
+		switch p.Output {
+		case "", "text", "table", "org", "obsidian":
+		default:
+			return fmt.Errorf("categoria %q: formato di output %q sconosciuto", key, p.Output)
+		}
+		if err := r.addMirrors(cat.Name, cat.URL, p.Mirrors); err != nil {
+			return err
+		}
+		if len(p.Boilerplate) > 0 {
+			if err := r.setCategoryBoilerplate(cat, p.Boilerplate); err != nil {
+				return err
+			}
+		}
+		r.prefs[cat.ID] = p
+	}
+	return nil
+}
+
+// applyCategoryPrefs sets the output format and description visibility
+// for showing cat: its own preferences where it has them, the general
+// settings otherwise.
+func (r *RssReader) applyCategoryPrefs(cat FeedCategory) {
+	r.output, r.hideDesc = r.baseOutput, r.baseHideDesc
+	p, ok := r.prefs[cat.ID]
+	if !ok {
+		return
+	}
+	if p.Output != "" {
+		r.output = p.Output
+	}
+	if p.NoDesc != nil {
+		r.hideDesc = *p.NoDesc
+	}
+}
+
+// feedConfig is a custom feed declared in the config file.
+type feedConfig struct {
+	Name    string   `toml:"name"`
+	URL     string   `toml:"url"`
+	Mirrors []string `toml:"mirrors,omitempty"`
+}
+
+// defaultConfig returns the built-in defaults.
+func defaultConfig() Config {
+	return Config{
+		Timeout:       10 * time.Second,
+		DialTimeout:   5 * time.Second,
+		TLSTimeout:    5 * time.Second,
+		HeaderTimeout: 10 * time.Second,
+		Output:        "text",
+		SortMenu:      "id",
+		Clean:         strings.Join(defaultCleanStages, ","),
+		LinkTemplate:  defaultLinkTemplate,
+		Attribution:   defaultAttribution,
+		ArchiveDays:   90,
+		ArchiveItems:  50000,
+		Locale:        "it",
+	}
+}
+
+// stringList is a flag.Value collecting every occurrence of a repeated flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// registerFlags binds every setting to a flag of fs, using the current
+// values as defaults.
+func (c *Config) registerFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&c.Timeout, "timeout", c.Timeout, "tempo massimo per scaricare un feed")
+	fs.DurationVar(&c.DialTimeout, "dial-timeout", c.DialTimeout, "tempo massimo per connettersi al server di un feed")
+	fs.DurationVar(&c.TLSTimeout, "tls-timeout", c.TLSTimeout, "tempo massimo per la negoziazione TLS")
+	fs.DurationVar(&c.HeaderTimeout, "header-timeout", c.HeaderTimeout, "tempo massimo di attesa della risposta dopo la richiesta")
+	fs.DurationVar(&c.CacheTTL, "cache-ttl", c.CacheTTL, "per quanto riusare un feed già scaricato senza richiederlo (es. 5m, 0: secondo il server)")
+	fs.StringVar(&c.Output, "output", c.Output, "formato di visualizzazione dei feed: text, table, org o obsidian")
+	fs.StringVar(&c.SortMenu, "sort-menu", c.SortMenu, "ordine del menu: id o usage (categorie più usate prima)")
+	fs.IntVar(&c.Width, "width", c.Width, "larghezza di impaginazione in colonne (predefinita: quella del terminale)")
+	fs.BoolVar(&c.NoDesc, "no-desc", c.NoDesc, "mostra solo i titoli, senza descrizioni")
+	fs.BoolVar(&c.ScreenReader, "screen-reader", c.ScreenReader, "output per lettori di schermo: niente colori né decorazioni")
+	fs.StringVar(&c.Clean, "clean", c.Clean, "fasi di pulizia delle descrizioni, in ordine (alt, tags, entities, spaces, boilerplate; vuoto: nessuna)")
+	fs.BoolVar(&c.NoAlt, "no-alt", c.NoAlt, "non riportare testo alternativo e didascalie delle immagini nelle descrizioni")
+	fs.Var(&c.Boilerplate, "boilerplate", "espressione regolare da rimuovere dalle descrizioni (ripetibile)")
+	fs.StringVar(&c.Proxy, "proxy", c.Proxy, "proxy HTTP o SOCKS5 per scaricare i feed (es. socks5://127.0.0.1:1080)")
+	fs.StringVar(&c.DataDir, "data-dir", c.DataDir, "cartella dei dati persistenti (predefinita: $XDG_DATA_HOME/adncli)")
+	fs.StringVar(&c.SSHTunnel, "ssh-tunnel", c.SSHTunnel, "scarica i feed attraverso un tunnel SSH verso `utente@host`")
+	fs.BoolVar(&c.Warmup, "warmup", c.Warmup, "apre in anticipo le connessioni ai server dei feed mentre mostra il menu")
+	fs.BoolVar(&c.Probe, "probe", c.Probe, "controlla DNS e TLS dei server dei feed prima di mostrare il menu")
+	fs.BoolVar(&c.NoStats, "no-stats", c.NoStats, "non mostrare il riepilogo della sessione all'uscita")
+	fs.BoolVar(&c.Verbose, "verbose", c.Verbose, "registra ogni richiesta HTTP su stderr")
+	fs.StringVar(&c.LinkTemplate, "link-template", c.LinkTemplate, "modello del comando md: {title}, {link} e {category} vengono sostituiti")
+	fs.StringVar(&c.Attribution, "attribution", c.Attribution, "piè di pagina dell'output org e Markdown: {title}, {link} e {copyright} del feed vengono sostituiti (vuoto: nessuno)")
+	fs.StringVar(&c.Locale, "locale", c.Locale, "lingua dei nomi di giorni e mesi: "+strings.Join(localeNames(), " o "))
+	fs.StringVar(&c.Category, "category", c.Category, "stampa le notizie delle categorie indicate (nome o numero, separate da virgola) ed esce")
+	fs.IntVar(&c.ArchiveDays, "archive-days", c.ArchiveDays, "giorni per cui l'archivio conserva le notizie (0: senza limite)")
+	fs.IntVar(&c.ArchiveItems, "archive-max-items", c.ArchiveItems, "numero massimo di notizie nell'archivio (0: senza limite)")
+	fs.IntVar(&c.HideFailingDays, "hide-failing-days", c.HideFailingDays, "nasconde dal menu le categorie che non funzionano da più di questi giorni (0: mai)")
+	fs.IntVar(&c.Limit, "limit", c.Limit, "numero massimo di notizie per categoria con --category (0: tutte)")
+	fs.BoolVar(&c.Resume, "resume", c.Resume, "all'avvio riapre l'ultima categoria consultata")
+	fs.StringVar(&c.Attach, "attach", c.Attach, "usa le notizie e la coda di un adncli serve in esecuzione (es. http://127.0.0.1:8080)")
+	fs.StringVar(&c.AttachToken, "attach-token", c.AttachToken, "token per --attach (o "+envPrefix+"ATTACH_TOKEN)")
+	fs.DurationVar(&c.Briefing, "briefing", c.Briefing, "stampa un briefing delle notizie più fresche per il tempo di lettura indicato (es. 5m) ed esce")
+	fs.StringVar(&c.BriefingCategories, "briefing-categories", c.BriefingCategories, "categorie del briefing separate da virgola (nome o numero, predefinito: tutte)")
+}
+
+// envName returns the environment variable overriding the named flag.
+func envName(prefix, flagName string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyEnv sets every flag of fs that has a matching variable named with
+// prefix, such as ADNCLI_ for the global flags. With only set, flags
+// missing from it are left alone. It must run before fs.Parse so that
+// flags keep the last word.
+func applyEnv(fs *flag.FlagSet, prefix string, lookup func(string) (string, bool), only map[string]bool) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || (only != nil && !only[f.Name]) {
+			return
+		}
+		name := envName(prefix, f.Name)
+		if value, ok := lookup(name); ok {
+			if setErr := f.Value.Set(value); setErr != nil {
+				err = fmt.Errorf("%s=%q: %w", name, value, setErr)
+			}
+		}
+	})
+	return err
+}
+
+// persistentFlags returns the names of the global flags that the config
+// file can also set. The others, such as --category and --briefing,
+// select a one-off mode, so a variable left in the environment must not
+// turn every run into it.
+func persistentFlags() map[string]bool {
+	names := make(map[string]bool)
+	t := reflect.TypeFor[Config]()
+	for i := range t.NumField() {
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("toml"), ",")
+		if key != "" && key != "-" {
+			names[strings.ReplaceAll(key, "_", "-")] = true
+		}
+	}
+	return names
+}
+
+// validate checks settings that flag parsing cannot.
+func (c *Config) validate() error {
+	switch c.Output {
+	case "text", "table", "org", "obsidian":
+	default:
+		return fmt.Errorf("formato di output %q sconosciuto", c.Output)
+	}
+	switch c.SortMenu {
+	case "id", "usage":
+	default:
+		return fmt.Errorf("ordinamento del menu %q sconosciuto", c.SortMenu)
+	}
+	if _, err := lookupLocale(c.Locale); err != nil {
+		return err
+	}
+	// A bare number in the config file is read as nanoseconds.
+	for _, t := range []time.Duration{c.Timeout, c.DialTimeout, c.TLSTimeout, c.HeaderTimeout} {
+		if t < 100*time.Millisecond {
+			return fmt.Errorf("timeout %s non valido, usa una durata come \"10s\"", t)
+		}
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("cache_ttl %s non valido", c.CacheTTL)
+	}
+	if c.Limit < 0 {
+		return fmt.Errorf("limite %d non valido", c.Limit)
+	}
+	if c.Width < 0 {
+		return fmt.Errorf("larghezza %d non valida", c.Width)
+	}
+	if c.HideFailingDays < 0 {
+		return fmt.Errorf("giorni %d non validi per --hide-failing-days", c.HideFailingDays)
+	}
+	if c.ArchiveDays < 0 || c.ArchiveItems < 0 {
+		return errors.New("i limiti dell'archivio non possono essere negativi")
+	}
+	if c.Proxy != "" {
+		if u, err := url.Parse(c.Proxy); err != nil || u.Host == "" {
+			return fmt.Errorf("proxy %q non valido", c.Proxy)
+		}
+	}
+	return nil
+}
+
+// configPath returns the config file location: $ADNCLI_CONFIG, or
+// config.toml in $XDG_CONFIG_HOME/adncli or ~/.config/adncli.
+func configPath() (string, error) {
+	if path := os.Getenv(envPrefix + "CONFIG"); path != "" {
+		return path, nil
+	}
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot locate home directory: %w", err)
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "adncli", "config.toml"), nil
+}
+
+// loadConfigFile overlays the config file, if any, on cfg. Unknown keys
+// are errors, so that typos do not go unnoticed.
+func loadConfigFile(cfg *Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	md, err := toml.DecodeFile(path, cfg)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return fmt.Errorf("%s: chiavi sconosciute: %s", path, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// addConfigFeeds appends the custom feeds of the config file to the
+// categories, numbered after the last one.
+func (r *RssReader) addConfigFeeds(feeds []feedConfig) error {
+	for _, f := range feeds {
+		name := strings.TrimSpace(f.Name)
+		if name == "" {
+			return fmt.Errorf("feed %q: nome mancante", f.URL)
+		}
+		if !validFeedURL(f.URL) {
+			return fmt.Errorf("feed %q: URL %q non valido", name, f.URL)
+		}
+		if _, ok := r.findCategory(name); ok {
+			return fmt.Errorf("feed %q: esiste già una categoria con questo nome", name)
+		}
+		if err := r.addMirrors(name, f.URL, f.Mirrors); err != nil {
+			return err
+		}
+		id := r.categories[len(r.categories)-1].ID + 1
+		r.categories = append(r.categories, FeedCategory{id, name, f.URL})
+	}
+	return nil
+}
+
+// loadConfig resolves the configuration from defaults, the config file,
+// the environment and the command-line arguments, returning the remaining
+// positional arguments.
+func loadConfig(args []string) (Config, []string, error) {
+	cfg := defaultConfig()
+	if err := loadConfigFile(&cfg); err != nil {
+		return cfg, nil, err
+	}
+
+	fs := flag.NewFlagSet("adncli", flag.ExitOnError)
+	cfg.registerFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Uso: adncli [opzioni] [comando]\n\nSenza comando adncli apre il menu interattivo. Comandi:\n")
+		for _, c := range commands {
+			fmt.Fprintf(fs.Output(), "  %-34s %s\n", c.usage, c.summary)
+		}
+		fmt.Fprintf(fs.Output(), "\nOpzioni:\n")
+		fs.PrintDefaults()
+		path, _ := configPath()
+		fmt.Fprintf(fs.Output(), "\nLe opzioni si possono impostare in %s (es. sort_menu = \"usage\")\n"+
+			"o con una variabile d'ambiente %s<NOME> (es. %s); le variabili\n"+
+			"prevalgono sul file e le opzioni sulla riga di comando su entrambi.\n"+
+			"--category, --limit, --briefing e --briefing-categories valgono solo sulla riga di comando.\n",
+			path, envPrefix, envName(envPrefix, "sort-menu"))
+	}
+	if err := applyEnv(fs, envPrefix, os.LookupEnv, persistentFlags()); err != nil {
+		return cfg, nil, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
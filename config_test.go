@@ -0,0 +1,54 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"ADNCLI_TIMEOUT":           "3s",
+		"ADNCLI_ARCHIVE_MAX_ITEMS": "7",
+		"ADNCLI_CATEGORY":          "Sport",
+		"ADNCLI_BRIEFING":          "5m",
+	}
+	cfg := defaultConfig()
+	fs := flag.NewFlagSet("adncli", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cfg.registerFlags(fs)
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	if err := applyEnv(fs, envPrefix, lookup, persistentFlags()); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"--timeout", "4s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Timeout != 4*time.Second {
+		t.Errorf("timeout %s, want the flag's 4s", cfg.Timeout)
+	}
+	if cfg.ArchiveItems != 7 {
+		t.Errorf("archive items %d, want 7 from the environment", cfg.ArchiveItems)
+	}
+	if cfg.Category != "" || cfg.Briefing != 0 {
+		t.Errorf("one-off modes read from the environment: category %q, briefing %s", cfg.Category, cfg.Briefing)
+	}
+
+	// Every flag is either a config key or one of the one-off modes.
+	persistent := persistentFlags()
+	oneOff := map[string]bool{"category": true, "limit": true, "briefing": true, "briefing-categories": true}
+	fs.VisitAll(func(f *flag.Flag) {
+		if persistent[f.Name] == oneOff[f.Name] {
+			t.Errorf("flag --%s: persistent %v, one-off %v", f.Name, persistent[f.Name], oneOff[f.Name])
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dateLocale holds the weekday and month names of a language, Sunday and
+// January first as in the time package.
+type dateLocale struct {
+	weekdays [7]string
+	months   [12]string
+}
+
+// dateLocales are the languages selectable with --locale.
+var dateLocales = map[string]dateLocale{
+	"it": {
+		weekdays: [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		months: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	},
+	"en": {
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+	},
+}
+
+// dates is the locale used to render dates, set from the configuration.
+var dates = dateLocales["it"]
+
+// localeNames returns the supported locales, sorted.
+func localeNames() []string {
+	names := make([]string, 0, len(dateLocales))
+	for name := range dateLocales {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// lookupLocale returns the named locale.
+func lookupLocale(name string) (dateLocale, error) {
+	l, ok := dateLocales[name]
+	if !ok {
+		return l, fmt.Errorf("lingua %q non supportata (disponibili: %s)", name, strings.Join(localeNames(), ", "))
+	}
+	return l, nil
+}
+
+// abbrev returns the first three letters of a name.
+func abbrev(name string) string {
+	r := []rune(name)
+	return string(r[:min(3, len(r))])
+}
+
+// long renders t in local time as "lun 12 gennaio, 14:30".
+func (l dateLocale) long(t time.Time) string {
+	t = t.Local()
+	return fmt.Sprintf("%s %d %s, %s", abbrev(l.weekdays[t.Weekday()]), t.Day(), l.months[t.Month()-1], t.Format("15:04"))
+}
+
+// short renders t in local time as "lun 12 gen 14:30", for columns and
+// summaries.
+func (l dateLocale) short(t time.Time) string {
+	t = t.Local()
+	return fmt.Sprintf("%s %d %s %s", abbrev(l.weekdays[t.Weekday()]), t.Day(), abbrev(l.months[t.Month()-1]), t.Format("15:04"))
+}
+
+// pubDate renders an item's publication date in the selected locale,
+// falling back to the raw value when it cannot be parsed.
+func pubDate(item Item) string {
+	if t, ok := parsePubDate(item.PubDate); ok {
+		return dates.long(t)
+	}
+	return strings.TrimSpace(item.PubDate)
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// exportFormats maps the formats accepted by export to output modes; md
+// is the Markdown note also used for Obsidian.
+var exportFormats = map[string]string{
+	"text":     "text",
+	"table":    "table",
+	"org":      "org",
+	"obsidian": "obsidian",
+	"md":       "obsidian",
+}
+
+// expandHome replaces a leading "~/" in path with the home directory.
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// exportView writes the feed shown last to path in the given format,
+// exactly as it is on screen: same tag filter, same descriptions, same
+// order. The file gets no color codes.
+func (r *RssReader) exportView(format, path string) error {
+	if r.current == nil {
+		return errNoFeed
+	}
+	output, ok := exportFormats[strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("formato %q sconosciuto: usa md, text, table, org o obsidian", format)
+	}
+	path, err := expandHome(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	// The chosen format wins over the screen reader layout, which is
+	// meant for the terminal.
+	content, prev, sr := r.out.content, r.output, r.screenReader
+	defer func() { r.out.content, r.output, r.screenReader = content, prev, sr }()
+	r.out.content = f
+	r.output = output
+	r.screenReader = false
+	withoutColors(func() { r.displayFeed(r.current) })
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.out.diag, "%sVista esportata in %s.%s\n", ColorGreen, path, ColorReset)
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// hiddenRetry is how long a category hidden for failing is left alone
+// before it is fetched again, to show it once it works again.
+const hiddenRetry = 6 * time.Hour
+
+// failingFor returns how long the feed has been failing, 0 if it works.
+func (st *feedStatus) failingFor(now time.Time) time.Duration {
+	if st == nil || st.FailingSince.IsZero() {
+		return 0
+	}
+	return now.Sub(st.FailingSince)
+}
+
+// hidden reports whether cat is left out of the menu for failing longer
+// than hideFailing.
+func (r *RssReader) hidden(cat FeedCategory, states *feedStates, now time.Time) bool {
+	return r.hideFailing > 0 && states.Feeds[cat.URL].failingFor(now) > r.hideFailing
+}
+
+// activeCategories returns the categories that are not hidden, in order.
+func (r *RssReader) activeCategories(states *feedStates) []FeedCategory {
+	if r.hideFailing == 0 {
+		return r.categories
+	}
+	now := time.Now()
+	var active []FeedCategory
+	for _, cat := range r.categories {
+		if !r.hidden(cat, states, now) {
+			active = append(active, cat)
+		}
+	}
+	return active
+}
+
+// retryDue reports whether cat is hidden for failing and has not been
+// tried for hiddenRetry.
+func (r *RssReader) retryDue(cat FeedCategory, states *feedStates, now time.Time) bool {
+	return r.hidden(cat, states, now) && now.Sub(states.Feeds[cat.URL].LastFailure) >= hiddenRetry
+}
+
+// aggregateCategories returns the categories to fetch for "all": those not
+// hidden for failing and the hidden ones due for a retry, or every one
+// when the feed states cannot be read.
+func (r *RssReader) aggregateCategories() []FeedCategory {
+	states, err := loadFeedStates()
+	if err != nil || r.hideFailing == 0 {
+		return r.categories
+	}
+	now := time.Now()
+	var fetch []FeedCategory
+	for _, cat := range r.categories {
+		if !r.hidden(cat, states, now) || r.retryDue(cat, states, now) {
+			fetch = append(fetch, cat)
+		}
+	}
+	return fetch
+}
+
+// retryHidden fetches the hidden categories due for a retry, so that the
+// menu shows again those that work. A success clears their failure; a
+// failure only postpones the next retry.
+func (r *RssReader) retryHidden() {
+	states, err := loadFeedStates()
+	if err != nil || r.hideFailing == 0 {
+		return
+	}
+	now := time.Now()
+	var due []FeedCategory
+	for _, cat := range r.categories {
+		if r.retryDue(cat, states, now) {
+			due = append(due, cat)
+		}
+	}
+	if len(due) > 0 {
+		r.fetchAll(context.Background(), due)
+	}
+}
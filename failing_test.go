@@ -0,0 +1,52 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAggregateCategories(t *testing.T) {
+	dataHome = t.TempDir()
+	t.Cleanup(func() { dataHome = "" })
+
+	now := time.Now()
+	r := &RssReader{
+		hideFailing: 24 * time.Hour,
+		categories: []FeedCategory{
+			{1, "Ultim'ora", "https://example.com/ultimora"},
+			{2, "Politica", "https://example.com/politica"},
+			{3, "Sport", "https://example.com/sport"},
+			{4, "Cultura", "https://example.com/cultura"},
+		},
+	}
+	states, err := readFeedStates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Failing, but not for long enough to be hidden.
+	states.get("https://example.com/politica").FailingSince = now.Add(-time.Hour)
+	// Hidden and retried recently.
+	sport := states.get("https://example.com/sport")
+	sport.FailingSince = now.Add(-72 * time.Hour)
+	sport.LastFailure = now.Add(-time.Hour)
+	// Hidden and due for a retry.
+	cultura := states.get("https://example.com/cultura")
+	cultura.FailingSince = now.Add(-72 * time.Hour)
+	cultura.LastFailure = now.Add(-hiddenRetry - time.Minute)
+	if err := states.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, cat := range r.aggregateCategories() {
+		got = append(got, cat.Name)
+	}
+	if want := []string{"Ultim'ora", "Politica", "Cultura"}; !slices.Equal(got, want) {
+		t.Errorf("aggregateCategories = %q, want %q", got, want)
+	}
+}
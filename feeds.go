@@ -0,0 +1,102 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// appendConfigFeed adds f to the config file as a [[feeds]] table. The
+// table is appended to the text rather than re-encoding the whole file,
+// which would lose the user's comments and layout. A symlinked config is
+// written through the link, and an existing file keeps its permissions;
+// a new one is private, since it may hold tokens and proxy credentials.
+func appendConfigFeed(f feedConfig) (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(struct {
+		Feeds []feedConfig `toml:"feeds"`
+	}{[]feedConfig{f}}); err != nil {
+		return "", err
+	}
+
+	perm := os.FileMode(0o600)
+	old, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		perm = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return "", err
+	}
+
+	// A [[feeds]] table cannot follow an inline feeds = [...] array.
+	meta, err := toml.Decode(string(old), &struct{}{})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if meta.IsDefined("feeds") && meta.Type("feeds") != "ArrayHash" {
+		return "", fmt.Errorf("%s: i feed sono definiti in linea (feeds = [...]); aggiungi %q a mano", path, f.Name)
+	}
+
+	if len(old) > 0 && !bytes.HasSuffix(old, []byte("\n")) {
+		old = append(old, '\n')
+	}
+	if len(old) > 0 {
+		old = append(old, '\n')
+	}
+	return path, writeFileAtomic(path, append(old, buf.Bytes()...), perm)
+}
+
+// runFeed implements "adncli feed add <nome> <url>" and "adncli feed list [--all]".
+func (r *RssReader) runFeed(args []string) error {
+	usage := &usageError{usage: "adncli feed add <nome> <url> | adncli feed list [--all]"}
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "list":
+		return r.runList(args[1:])
+	case "add":
+		if len(args) != 3 {
+			return usage
+		}
+		f := feedConfig{Name: args[1], URL: args[2]}
+		// Validate against the categories the menu would show.
+		if err := r.addConfigFeeds([]feedConfig{f}); err != nil {
+			return err
+		}
+		path, err := appendConfigFeed(f)
+		if err != nil {
+			return err
+		}
+		added := r.categories[len(r.categories)-1]
+		fmt.Fprintf(r.out.diag, "%sFeed %q aggiunto come categoria %d in %s.%s\n", ColorGreen, added.Name, added.ID, path, ColorReset)
+		return nil
+	default:
+		return usage
+	}
+}
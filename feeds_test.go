@@ -0,0 +1,89 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestAppendConfigFeed(t *testing.T) {
+	feed := feedConfig{Name: "Blog", URL: "https://example.com/feed.xml"}
+
+	t.Run("new file is private", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		t.Setenv(envPrefix+"CONFIG", path)
+		if _, err := appendConfigFeed(feed); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("mode %o, want 600", perm)
+		}
+	})
+
+	t.Run("symlink and mode are kept", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "dotfiles.toml")
+		if err := os.WriteFile(target, []byte("output = \"org\"\n"), 0o640); err != nil {
+			t.Fatal(err)
+		}
+		link := filepath.Join(dir, "config.toml")
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(envPrefix+"CONFIG", link)
+		if _, err := appendConfigFeed(feed); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := os.Lstat(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			t.Error("config.toml is no longer a symlink")
+		}
+		info, err = os.Stat(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o640 {
+			t.Errorf("mode %o, want 640", perm)
+		}
+
+		var cfg Config
+		if _, err := toml.DecodeFile(target, &cfg); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Output != "org" || len(cfg.Feeds) != 1 || cfg.Feeds[0].Name != feed.Name || cfg.Feeds[0].URL != feed.URL {
+			t.Errorf("config after append: %+v", cfg)
+		}
+	})
+
+	t.Run("inline feeds are refused", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		inline := "feeds = [{name = \"A\", url = \"https://a.example/feed\"}]\n"
+		if err := os.WriteFile(path, []byte(inline), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(envPrefix+"CONFIG", path)
+		_, err := appendConfigFeed(feed)
+		if err == nil || !strings.Contains(err.Error(), "in linea") {
+			t.Fatalf("got error %v, want one about inline feeds", err)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != inline {
+			t.Errorf("config was modified:\n%s", data)
+		}
+	})
+}
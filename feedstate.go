@@ -0,0 +1,213 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// feedStatesMu serializes read-modify-write cycles of the feed status
+// file, which concurrent fetches would otherwise overwrite, and guards
+// pendingStates.
+var feedStatesMu sync.Mutex
+
+// pendingStates holds the changes to feed statuses not written yet, by
+// feed URL, so that a fetch rewrites the status file once rather than
+// once per change. flushFeedStates writes them.
+var pendingStates = make(map[string][]func(*feedStatus))
+
+// feedStatus is what adncli remembers about a feed between runs.
+type feedStatus struct {
+	// LastSuccess is when the feed last returned at least one item,
+	// LastItems how many, LastHeadline the first of them and
+	// LastPublished its publication date, if it had a valid one.
+	LastSuccess   time.Time `json:"last_success,omitzero"`
+	LastItems     int       `json:"last_items,omitempty"`
+	LastHeadline  string    `json:"last_headline,omitempty"`
+	LastPublished time.Time `json:"last_published,omitzero"`
+
+	// FailingSince is when fetching the feed started failing, zero while
+	// the last fetch succeeded, and LastFailure when it last failed.
+	FailingSince time.Time `json:"failing_since,omitzero"`
+	LastFailure  time.Time `json:"last_failure,omitzero"`
+
+	// LastSource is the mirror that served the last fetch, empty when
+	// it was the feed's own URL.
+	LastSource string `json:"last_source,omitempty"`
+
+	// ETag and LastModified are the validators of the cached body, sent
+	// back so that the server can answer 304 Not Modified.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// FreshUntil is when the cached body stops being reusable without a
+	// request, from Cache-Control max-age or the configured cache_ttl.
+	FreshUntil time.Time `json:"fresh_until,omitzero"`
+
+	// Selections counts how often the feed was picked from the menu, and
+	// LastViewed when it last was.
+	Selections int       `json:"selections,omitempty"`
+	LastViewed time.Time `json:"last_viewed,omitzero"`
+
+	// HourlyRate is the usual number of items published per hour, updated
+	// at RateUpdated, and LastHourItems how many the last fetch found
+	// published in the hour before it.
+	HourlyRate    float64   `json:"hourly_rate,omitempty"`
+	RateUpdated   time.Time `json:"rate_updated,omitzero"`
+	LastHourItems int       `json:"last_hour_items,omitempty"`
+}
+
+// feedStates is the persistent per-feed status, keyed by feed URL.
+type feedStates struct {
+	path  string
+	Feeds map[string]*feedStatus `json:"feeds"`
+}
+
+// loadFeedStates returns the feed statuses in the data directory, with
+// the changes not written yet applied.
+func loadFeedStates() (*feedStates, error) {
+	feedStatesMu.Lock()
+	defer feedStatesMu.Unlock()
+	s, err := readFeedStates()
+	if err != nil {
+		return nil, err
+	}
+	s.applyPending()
+	return s, nil
+}
+
+// readFeedStates reads the feed status file as it is on disk.
+func readFeedStates() (*feedStates, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &feedStates{
+		path:  filepath.Join(dir, "feeds.json"),
+		Feeds: make(map[string]*feedStatus),
+	}
+	if err := loadJSON(s.path, s); err != nil {
+		return nil, err
+	}
+	if s.Feeds == nil {
+		s.Feeds = make(map[string]*feedStatus)
+	}
+	return s, nil
+}
+
+// applyPending applies the queued changes to s, keeping them queued.
+func (s *feedStates) applyPending() {
+	for url, changes := range pendingStates {
+		st := s.get(url)
+		for _, change := range changes {
+			change(st)
+		}
+	}
+}
+
+// updateFeedState queues change to the status of url. Changes are applied
+// in order, to every status read until flushFeedStates writes them.
+func updateFeedState(url string, change func(*feedStatus)) {
+	feedStatesMu.Lock()
+	defer feedStatesMu.Unlock()
+	pendingStates[url] = append(pendingStates[url], change)
+}
+
+// flushFeedStates writes the queued changes with one rewrite of the
+// status file. It is best effort: a state file that cannot be written
+// must not break reading news.
+func flushFeedStates() {
+	feedStatesMu.Lock()
+	defer feedStatesMu.Unlock()
+	if len(pendingStates) == 0 {
+		return
+	}
+	if s, err := readFeedStates(); err == nil {
+		s.applyPending()
+		s.save()
+	}
+	clear(pendingStates)
+}
+
+// get returns the status of url, creating an empty one if needed.
+func (s *feedStates) get(url string) *feedStatus {
+	st, ok := s.Feeds[url]
+	if !ok {
+		st = &feedStatus{}
+		s.Feeds[url] = st
+	}
+	return st
+}
+
+// save writes the statuses back to disk.
+func (s *feedStates) save() error {
+	return saveJSON(s.path, s)
+}
+
+// recordFetch remembers a successful fetch of url, served from source.
+func recordFetch(url, source string, rss *Rss) {
+	now := time.Now()
+	updateFeedState(url, func(st *feedStatus) {
+		// An empty feed still works: only clear a failure.
+		st.FailingSince = time.Time{}
+		if len(rss.Channel.Items) == 0 {
+			return
+		}
+		st.LastSuccess = now
+		st.LastItems = len(rss.Channel.Items)
+		st.LastHeadline = rss.Channel.Items[0].Title
+		st.LastPublished, _ = parsePubDate(rss.Channel.Items[0].PubDate)
+		st.LastSource = ""
+		if source != url {
+			st.LastSource = source
+		}
+		st.updateVolume(rss, now)
+	})
+}
+
+// recordFailure remembers that fetching url failed. FailingSince keeps
+// the first failure after a success.
+func recordFailure(url string) {
+	now := time.Now()
+	updateFeedState(url, func(st *feedStatus) {
+		if st.FailingSince.IsZero() {
+			st.FailingSince = now
+		}
+		st.LastFailure = now
+	})
+}
+
+// recordSelection counts a menu selection of url.
+func recordSelection(url string) {
+	now := time.Now()
+	updateFeedState(url, func(st *feedStatus) {
+		st.Selections++
+		st.LastViewed = now
+	})
+}
+
+// lastViewed returns the category picked from the menu most recently, if
+// any of categories was ever picked.
+func lastViewed(categories []FeedCategory) (FeedCategory, bool) {
+	states, err := loadFeedStates()
+	if err != nil {
+		return FeedCategory{}, false
+	}
+
+	var (
+		last  FeedCategory
+		when  time.Time
+		found bool
+	)
+	for _, cat := range categories {
+		if st, ok := states.Feeds[cat.URL]; ok && st.LastViewed.After(when) {
+			last, when, found = cat, st.LastViewed, true
+		}
+	}
+	return last, found
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFlushFeedStates(t *testing.T) {
+	dataHome = t.TempDir()
+	t.Cleanup(func() { dataHome = "" })
+
+	const url = "https://example.com/rss"
+	rss := &Rss{Channel: Channel{Items: []Item{{Title: "Prima notizia"}}}}
+	recordSelection(url)
+	saveValidators(url, validators{etag: `"v1"`})
+	recordFetch(url, url, rss)
+
+	path := filepath.Join(dataHome, "feeds.json")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("feeds.json written before the flush (err %v)", err)
+	}
+	// Queued changes are visible before they are written.
+	states, err := loadFeedStates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := states.get(url); st.Selections != 1 || st.LastHeadline != "Prima notizia" {
+		t.Errorf("before flush: got %+v", st)
+	}
+
+	flushFeedStates()
+	recordSelection(url)
+	flushFeedStates()
+
+	states, err = readFeedStates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := states.get(url)
+	if st.Selections != 2 || st.ETag != `"v1"` || st.LastHeadline != "Prima notizia" {
+		t.Errorf("after flush: got %+v", st)
+	}
+}
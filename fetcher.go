@@ -0,0 +1,89 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// userAgent identifies adncli to the servers it talks to.
+const userAgent = "Adnkronos-CLI-Reader/1.0"
+
+// Fetcher performs HTTP requests on behalf of the reader. *http.Client
+// satisfies it; middlewares wrap it to add cross-cutting behavior.
+// Implementations must be safe for concurrent use.
+type Fetcher interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// FetcherFunc adapts an ordinary function to the Fetcher interface.
+type FetcherFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f FetcherFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware decorates a Fetcher.
+type Middleware func(next Fetcher) Fetcher
+
+// chain wraps f with the given middlewares; the first one is outermost.
+func chain(f Fetcher, middlewares ...Middleware) Fetcher {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		f = middlewares[i](f)
+	}
+	return f
+}
+
+// newTransport returns a transport like http.DefaultTransport with its own
+// limits on connecting, on the TLS handshake and on waiting for the
+// response headers. The time spent reading the body is only bounded by
+// the client's overall timeout.
+func newTransport(dial, tlsHandshake, header time.Duration) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = (&net.Dialer{Timeout: dial, KeepAlive: 30 * time.Second}).DialContext
+	t.TLSHandshakeTimeout = tlsHandshake
+	t.ResponseHeaderTimeout = header
+	return t
+}
+
+// withUserAgent sets the User-Agent header unless the request has one.
+func withUserAgent(ua string) Middleware {
+	return func(next Fetcher) Fetcher {
+		return FetcherFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("User-Agent") == "" {
+				req.Header.Set("User-Agent", ua)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// withLogging writes one line per request with its outcome and duration.
+func withLogging(w io.Writer) Middleware {
+	logger := log.New(w, "http: ", log.Ltime)
+	return func(next Fetcher) Fetcher {
+		return FetcherFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			elapsed := time.Since(start).Round(time.Millisecond)
+			if err != nil {
+				logger.Printf("%s %s: %v (%s)", req.Method, req.URL, err, elapsed)
+				return nil, err
+			}
+			logger.Printf("%s %s: %s (%s)", req.Method, req.URL, resp.Status, elapsed)
+			return resp, nil
+		})
+	}
+}
+
+// use appends middlewares around the reader's current fetcher.
+func (r *RssReader) use(middlewares ...Middleware) {
+	r.fetcher = chain(r.fetcher, middlewares...)
+}
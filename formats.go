@@ -0,0 +1,472 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"strings"
+)
+
+// sniffSize is how much of a response is inspected to detect its format.
+const sniffSize = 4096
+
+// feedFormat names a syndication format.
+type feedFormat string
+
+const (
+	formatRSS2     feedFormat = "RSS 2.0"
+	formatRSS1     feedFormat = "RSS 1.0"
+	formatAtom     feedFormat = "Atom"
+	formatJSONFeed feedFormat = "JSON Feed"
+)
+
+// formatError reports a document adncli cannot read, naming the format
+// when it was recognised and the root element or value either way.
+type formatError struct {
+	format feedFormat
+	root   string
+}
+
+func (e *formatError) Error() string {
+	if e.format != "" {
+		return fmt.Sprintf("unsupported feed format %s (root %s)", e.format, e.root)
+	}
+	if e.root != "" {
+		return fmt.Sprintf("unsupported feed format: root %s", e.root)
+	}
+	return "unsupported feed format: neither XML nor JSON"
+}
+
+// sniffFormat inspects the start of br, without consuming it, and returns
+// the detected format, if any, and a description of the root element.
+func sniffFormat(br *bufio.Reader) (feedFormat, string) {
+	data, _ := br.Peek(sniffSize)
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.TrimLeft(data, " \t\r\n")
+
+	if bytes.HasPrefix(data, []byte("{")) {
+		if bytes.Contains(data, []byte("jsonfeed.org/version")) {
+			return formatJSONFeed, "JSON object"
+		}
+		return "", "JSON object"
+	}
+
+	start := bytes.IndexByte(data, '<')
+	if start < 0 {
+		return "", ""
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data[start:]))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", ""
+		}
+		el, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		root := "<" + el.Name.Local + ">"
+		switch {
+		case el.Name.Local == "rss":
+			return formatRSS2, root
+		case el.Name.Local == "RDF":
+			return formatRSS1, root
+		case el.Name.Local == "feed" && el.Name.Space == "http://www.w3.org/2005/Atom":
+			return formatAtom, root
+		}
+		return "", root
+	}
+}
+
+// jsonFeedType is the media type of JSON Feed documents.
+const jsonFeedType = "application/feed+json"
+
+// decodeFeed detects the format of body and decodes it with the matching
+// parser. contentType is the Content-Type of the response, if known: a
+// JSON object served as application/feed+json is read as a JSON Feed even
+// when its version is not among the first bytes.
+func decodeFeed(body io.Reader, contentType string) (*Rss, error) {
+	br := bufio.NewReaderSize(body, sniffSize)
+	format, root := sniffFormat(br)
+	if format == "" && root == "JSON object" {
+		if mt, _, err := mime.ParseMediaType(contentType); err == nil && mt == jsonFeedType {
+			format = formatJSONFeed
+		}
+	}
+	switch format {
+	case formatRSS2:
+		return decodeRss(normalizeXML(br))
+	case formatRSS1:
+		return decodeRDF(normalizeXML(br))
+	case formatAtom:
+		return decodeAtom(normalizeXML(br))
+	case formatJSONFeed:
+		return decodeJSONFeed(br)
+	}
+	return nil, &formatError{format: format, root: root}
+}
+
+// rdfItem is an RSS 1.0 item, dated and tagged with Dublin Core.
+type rdfItem struct {
+	About       string   `xml:"about,attr"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Date        string   `xml:"date"`
+	Subjects    []string `xml:"subject"`
+}
+
+// decodeRDF decodes an RSS 1.0 document, where items follow the channel
+// instead of being nested in it.
+func decodeRDF(body io.Reader) (*Rss, error) {
+	rec := &rawRecorder{r: body}
+	dec := xml.NewDecoder(rec)
+
+	var rss Rss
+	depth := 0
+	inChannel := false
+	for {
+		start := dec.InputOffset()
+		rec.mark(start)
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case depth == 1:
+				continue
+			case depth == 2 && t.Name.Local == "channel":
+				inChannel = true
+				continue
+			case depth == 2 && t.Name.Local == "item":
+				var it rdfItem
+				if err = dec.DecodeElement(&it, &t); err == nil {
+					rss.Channel.Items = append(rss.Channel.Items, Item{
+						GUID:        it.About,
+						Title:       it.Title,
+						Link:        strings.TrimSpace(it.Link),
+						Description: it.Description,
+						PubDate:     it.Date,
+						Categories:  it.Subjects,
+						Raw:         rec.slice(start, dec.InputOffset()),
+					})
+				}
+			case depth == 3 && inChannel:
+				switch t.Name.Local {
+				case "title":
+					err = dec.DecodeElement(&rss.Channel.Title, &t)
+				case "link":
+					err = dec.DecodeElement(&rss.Channel.Link, &t)
+				case "description":
+					err = dec.DecodeElement(&rss.Channel.Description, &t)
+				case "language":
+					err = dec.DecodeElement(&rss.Channel.Language, &t)
+				case "rights":
+					err = dec.DecodeElement(&rss.Channel.Copyright, &t)
+				default:
+					err = dec.Skip()
+				}
+			default:
+				err = dec.Skip()
+			}
+			if err != nil {
+				return nil, err
+			}
+			// DecodeElement and Skip consume the end element too.
+			depth--
+		case xml.EndElement:
+			depth--
+			if t.Name.Local == "channel" {
+				inChannel = false
+			}
+		}
+	}
+	return &rss, nil
+}
+
+// atomLink is an Atom <link>; rel defaults to "alternate".
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// atomText is an Atom text construct, whose type says whether the content
+// is plain text, escaped HTML or inline XHTML.
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Body  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// String returns the text as it is fed to the cleaning pipeline: inline
+// XHTML keeps its markup, which the pipeline strips like HTML.
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return t.Inner
+	}
+	return t.Body
+}
+
+// plain returns the text without markup, for titles, which are shown
+// as they are rather than run through the cleaning pipeline.
+func (t atomText) plain() string {
+	s := t.String()
+	if t.Type == "html" || t.Type == "xhtml" {
+		s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	return s
+}
+
+// atomEntry is an Atom <entry>.
+type atomEntry struct {
+	ID         string     `xml:"id"`
+	Title      atomText   `xml:"title"`
+	Links      []atomLink `xml:"link"`
+	Summary    atomText   `xml:"summary"`
+	Content    atomText   `xml:"content"`
+	Published  string     `xml:"published"`
+	Updated    string     `xml:"updated"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+// alternate returns the href of the alternate link, the page the feed or
+// entry describes.
+func alternate(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
+
+// item converts the entry to the internal model, preferring the summary
+// to the full content and the publication date to the last update.
+func (e atomEntry) item() Item {
+	it := Item{
+		GUID:        strings.TrimSpace(e.ID),
+		Title:       e.Title.plain(),
+		Link:        alternate(e.Links),
+		Description: e.Summary.String(),
+		PubDate:     e.Published,
+	}
+	if strings.TrimSpace(it.Description) == "" {
+		it.Description = e.Content.String()
+	}
+	if it.PubDate == "" {
+		it.PubDate = e.Updated
+	}
+	for _, c := range e.Categories {
+		if c.Label != "" {
+			it.Categories = append(it.Categories, c.Label)
+		} else if c.Term != "" {
+			it.Categories = append(it.Categories, c.Term)
+		}
+	}
+	return it
+}
+
+// decodeAtom decodes an Atom feed one entry at a time into the same model
+// as RSS.
+func decodeAtom(body io.Reader) (*Rss, error) {
+	rec := &rawRecorder{r: body}
+	dec := xml.NewDecoder(rec)
+
+	var rss Rss
+	depth := 0
+	for {
+		start := dec.InputOffset()
+		rec.mark(start)
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "lang" {
+						rss.Channel.Language = attr.Value
+					}
+				}
+				continue
+			}
+
+			ch := &rss.Channel
+			switch t.Name.Local {
+			case "entry":
+				var e atomEntry
+				if err = dec.DecodeElement(&e, &t); err == nil {
+					item := e.item()
+					item.Raw = rec.slice(start, dec.InputOffset())
+					ch.Items = append(ch.Items, item)
+				}
+			case "title", "subtitle":
+				var text atomText
+				if err = dec.DecodeElement(&text, &t); err == nil {
+					if t.Name.Local == "title" {
+						ch.Title = text.plain()
+					} else {
+						ch.Description = text.String()
+					}
+				}
+			case "link":
+				var l atomLink
+				if err = dec.DecodeElement(&l, &t); err == nil && ch.Link == "" {
+					ch.Link = alternate([]atomLink{l})
+				}
+			case "updated":
+				err = dec.DecodeElement(&ch.LastBuildDate, &t)
+			case "generator":
+				err = dec.DecodeElement(&ch.Generator, &t)
+			case "rights":
+				var text atomText
+				if err = dec.DecodeElement(&text, &t); err == nil {
+					ch.Copyright = text.String()
+				}
+			case "logo", "icon":
+				var src string
+				if err = dec.DecodeElement(&src, &t); err == nil && (ch.Image == nil || t.Name.Local == "logo") {
+					ch.Image = &Image{URL: strings.TrimSpace(src), Title: ch.Title, Link: ch.Link}
+				}
+			default:
+				err = dec.Skip()
+			}
+			if err != nil {
+				return nil, err
+			}
+			depth--
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return &rss, nil
+}
+
+// jsonID is a JSON Feed item id. The spec wants a string, but some feeds
+// publish numbers, which are kept as their decimal text.
+type jsonID string
+
+func (id *jsonID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = jsonID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = jsonID(n)
+	return nil
+}
+
+// jsonFeedItem is an item of a JSON Feed. Every field is optional, but an
+// item has at least content_html or content_text.
+type jsonFeedItem struct {
+	ID            jsonID   `json:"id"`
+	URL           string   `json:"url"`
+	ExternalURL   string   `json:"external_url"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary"`
+	ContentHTML   string   `json:"content_html"`
+	ContentText   string   `json:"content_text"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags"`
+}
+
+// jsonFeed is a JSON Feed (https://jsonfeed.org/version/1.1) document.
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	Description string         `json:"description"`
+	Icon        string         `json:"icon"`
+	Favicon     string         `json:"favicon"`
+	Language    string         `json:"language"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+// item converts the JSON Feed item to the internal model. Like Atom
+// entries, it prefers the summary to the full content; title-less items,
+// common in microblogs, take their title from the first line of the text.
+func (j jsonFeedItem) item() Item {
+	it := Item{
+		GUID:        string(j.ID),
+		Title:       j.Title,
+		Link:        strings.TrimSpace(j.URL),
+		Description: j.Summary,
+		PubDate:     j.DatePublished,
+		Categories:  j.Tags,
+	}
+	if it.Link == "" {
+		it.Link = strings.TrimSpace(j.ExternalURL)
+	}
+	if strings.TrimSpace(it.Description) == "" {
+		it.Description = j.ContentHTML
+	}
+	if strings.TrimSpace(it.Description) == "" {
+		it.Description = j.ContentText
+	}
+	if it.PubDate == "" {
+		it.PubDate = j.DateModified
+	}
+	if strings.TrimSpace(it.Title) == "" {
+		first, _, _ := strings.Cut(strings.TrimSpace(j.ContentText), "\n")
+		it.Title = truncate(first, 80)
+	}
+	return it
+}
+
+// decodeJSONFeed decodes a JSON Feed into the same model as RSS.
+func decodeJSONFeed(body io.Reader) (*Rss, error) {
+	var feed jsonFeed
+	if err := json.NewDecoder(body).Decode(&feed); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(feed.Version, "https://jsonfeed.org/version/") {
+		return nil, &formatError{root: "JSON object"}
+	}
+
+	var rss Rss
+	ch := &rss.Channel
+	ch.Title = feed.Title
+	ch.Link = feed.HomePageURL
+	ch.Description = feed.Description
+	ch.Language = feed.Language
+	if icon := cmp.Or(feed.Icon, feed.Favicon); icon != "" {
+		ch.Image = &Image{URL: icon, Title: feed.Title, Link: feed.HomePageURL}
+	}
+	for _, j := range feed.Items {
+		ch.Items = append(ch.Items, j.item())
+	}
+	return &rss, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const (
+	rss2Doc = "\xef\xbb\xbf\n" + `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>ANSA</title>
+<item><title>Primo</title><link>https://example.com/1</link><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel></rss>`
+
+	rdfDoc = `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel rdf:about="https://example.com/"><title>ANSA</title></channel>
+<item rdf:about="https://example.com/1"><title>Primo</title><link>https://example.com/1</link><dc:date>2006-01-02T15:04:05Z</dc:date></item>
+</rdf:RDF>`
+
+	atomDoc = `<feed xmlns="http://www.w3.org/2005/Atom"><title>ANSA</title>
+<entry><title>Primo</title><link rel="alternate" href="https://example.com/1"/><published>2006-01-02T15:04:05Z</published></entry>
+</feed>`
+
+	jsonFeedDoc = `{"version": "https://jsonfeed.org/version/1.1", "title": "ANSA",
+"items": [{"id": "1", "url": "https://example.com/1", "title": "Primo", "date_published": "2006-01-02T15:04:05Z"}]}`
+)
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		format feedFormat
+		root   string
+	}{
+		{"rss2", rss2Doc, formatRSS2, "<rss>"},
+		{"rss1", rdfDoc, formatRSS1, "<RDF>"},
+		{"atom", `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`, formatAtom, "<feed>"},
+		{"json feed", ` {"version": "https://jsonfeed.org/version/1.1"}`, formatJSONFeed, "JSON object"},
+		{"plain json", `{"title": "x"}`, "", "JSON object"},
+		{"html", "<!DOCTYPE html><html></html>", "", "<html>"},
+		{"text", "not a feed", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, root := sniffFormat(bufio.NewReader(strings.NewReader(tt.doc)))
+			if format != tt.format || root != tt.root {
+				t.Errorf("got %q %q, want %q %q", format, root, tt.format, tt.root)
+			}
+		})
+	}
+}
+
+func TestDecodeFeed(t *testing.T) {
+	// A JSON Feed whose version follows more than sniffSize bytes can only
+	// be told apart by its Content-Type.
+	lateVersion := `{"title": "ANSA", "description": "` + strings.Repeat("x", sniffSize) +
+		`", "items": [{"id": "1", "url": "https://example.com/1", "title": "Primo", "date_published": "2006-01-02T15:04:05Z"}],
+"version": "https://jsonfeed.org/version/1.1"}`
+
+	tests := []struct {
+		name        string
+		doc         string
+		contentType string
+		date        string
+	}{
+		{"rss2", rss2Doc, "", "Mon, 02 Jan 2006 15:04:05 GMT"},
+		{"rss1", rdfDoc, "", "2006-01-02T15:04:05Z"},
+		{"atom", atomDoc, "", "2006-01-02T15:04:05Z"},
+		{"json feed", jsonFeedDoc, "", "2006-01-02T15:04:05Z"},
+		{"json feed by content type", lateVersion, "application/feed+json; charset=utf-8", "2006-01-02T15:04:05Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rss, err := decodeFeed(strings.NewReader(tt.doc), tt.contentType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rss.Channel.Title != "ANSA" {
+				t.Errorf("channel title %q, want %q", rss.Channel.Title, "ANSA")
+			}
+			if len(rss.Channel.Items) != 1 {
+				t.Fatalf("got %d items, want 1", len(rss.Channel.Items))
+			}
+			it := rss.Channel.Items[0]
+			if it.Title != "Primo" || it.Link != "https://example.com/1" || it.PubDate != tt.date {
+				t.Errorf("item %q %q %q, want %q %q %q", it.Title, it.Link, it.PubDate, "Primo", "https://example.com/1", tt.date)
+			}
+		})
+	}
+}
+
+func TestDecodeFeedUnsupported(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         string
+		contentType string
+		want        string
+	}{
+		{"html page", "<!DOCTYPE html><html><body></body></html>", "text/html", "unsupported feed format: root <html>"},
+		{"atom without namespace", "<feed><title>x</title></feed>", "", "unsupported feed format: root <feed>"},
+		{"plain json", `{"title": "x"}`, "application/json", "unsupported feed format: root JSON object"},
+		{"text", "not a feed", "", "unsupported feed format: neither XML nor JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeFeed(strings.NewReader(tt.doc), tt.contentType)
+			var ferr *formatError
+			if !errors.As(err, &ferr) {
+				t.Fatalf("got error %v, want a formatError", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("got %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAtomTitles(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"text", `<title>Plain &amp; simple</title>`, "Plain & simple"},
+		{"html", `<title type="html">&lt;b&gt;Hi&lt;/b&gt; &amp;amp; bye</title>`, "Hi & bye"},
+		{"html cdata", `<title type="html"><![CDATA[Primo &amp; <i>post</i>]]></title>`, "Primo & post"},
+		{"xhtml", `<title type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml">Hello <em>w</em></div></title>`, "Hello w"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := `<feed xmlns="http://www.w3.org/2005/Atom">` + tt.title +
+				`<entry>` + tt.title + `<link href="https://example.com/1"/></entry></feed>`
+			rss, err := decodeAtom(strings.NewReader(doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rss.Channel.Title != tt.want {
+				t.Errorf("feed title %q, want %q", rss.Channel.Title, tt.want)
+			}
+			if len(rss.Channel.Items) != 1 {
+				t.Fatalf("got %d entries, want 1", len(rss.Channel.Items))
+			}
+			if got := rss.Channel.Items[0].Title; got != tt.want {
+				t.Errorf("entry title %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// errNoFeed is returned by the commands that act on the feed shown last.
+var errNoFeed = errors.New("nessun feed caricato")
+
+// itemError reports an item number outside the feed shown last.
+type itemError struct {
+	arg   string
+	count int
+}
+
+func (e *itemError) Error() string { return fmt.Sprintf("notizia %q non valida", e.arg) }
+
+// choiceError reports menu input matching neither a category nor a command.
+type choiceError struct {
+	input string
+}
+
+func (e *choiceError) Error() string { return fmt.Sprintf("scelta %q non valida", e.input) }
+
+// usageError reports a command typed without the argument it needs, or
+// with a flag it does not accept.
+type usageError struct {
+	usage string
+	err   error // the flag error, already reported by the flag package
+}
+
+func (e *usageError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return "uso: " + e.usage
+}
+
+func (e *usageError) Unwrap() error { return e.err }
+
+// commandNames lists the menu commands, for suggestions.
+var commandNames = []string{"info", "open", "queue", "s", "d", "tag", "mail", "qr", "peek", "raw", "task", "md", "why", "export", "help"}
+
+// hint maps an error to a suggestion on what to type instead, or "" when
+// there is nothing useful to add.
+func (r *RssReader) hint(err error) string {
+	var (
+		itemErr   *itemError
+		choiceErr *choiceError
+		usageErr  *usageError
+	)
+	switch {
+	case errors.Is(err, errNoFeed):
+		return fmt.Sprintf("scegli prima una categoria dal menu, da 1 a %d.", len(r.categories))
+	case errors.As(err, &itemErr):
+		if itemErr.count == 0 {
+			return "il feed corrente non ha notizie."
+		}
+		return fmt.Sprintf("le notizie del feed corrente vanno da 1 a %d.", itemErr.count)
+	case errors.As(err, &usageErr):
+		return "digita help per l'elenco dei comandi."
+	case errors.As(err, &choiceErr):
+		return r.choiceHint(choiceErr.input)
+	}
+	return ""
+}
+
+// choiceHint suggests the category or command closest to a rejected
+// menu input.
+func (r *RssReader) choiceHint(input string) string {
+	valid := fmt.Sprintf("le categorie vanno da 1 a %d, a per tutte, 0 per uscire; digita help per i comandi.", len(r.categories))
+	if _, err := strconv.Atoi(input); err == nil {
+		return valid
+	}
+
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return valid
+	}
+	if cmd, ok := nearest(fields[0], commandNames); ok {
+		return fmt.Sprintf("forse intendevi il comando %q?", strings.Join(append([]string{cmd}, fields[1:]...), " "))
+	}
+
+	names := make([]string, len(r.categories))
+	for i, cat := range r.categories {
+		names[i] = cat.Name
+	}
+	if name, ok := nearest(input, names); ok {
+		cat, _ := r.findCategory(name)
+		return fmt.Sprintf("forse intendevi %s? Digita il suo numero, %d.", cat.Name, cat.ID)
+	}
+	return valid
+}
+
+// nearest returns the candidate closest to input, ignoring case and
+// punctuation, if it is close enough to be a plausible typo. A candidate
+// starting with input counts as an exact match.
+func nearest(input string, candidates []string) (string, bool) {
+	normalize := func(s string) string {
+		return strings.Map(func(c rune) rune {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				return unicode.ToLower(c)
+			}
+			return -1
+		}, s)
+	}
+
+	in := normalize(input)
+	n := len([]rune(in))
+	if n < 2 {
+		return "", false
+	}
+
+	// Allow one typo in short words, a third of the letters in longer ones.
+	limit := 1
+	if n >= 4 {
+		limit = max(2, n/3)
+	}
+	best, bestDist := "", limit+1
+	for _, c := range candidates {
+		norm := normalize(c)
+		dist := editDistance(in, norm)
+		if n >= 3 && strings.HasPrefix(norm, in) {
+			dist = 0
+		}
+		if dist < bestDist {
+			best, bestDist = c, dist
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// reportError prints an error from the interactive loop followed by its
+// hint, if any.
+func (r *RssReader) reportError(err error) {
+	fmt.Fprintf(r.out.diag, "%s>> Errore: %v%s\n", ColorRed, err, ColorReset)
+	if h := r.hint(err); h != "" {
+		fmt.Fprintf(r.out.diag, "%s   Suggerimento: %s%s\n", ColorYellow, h, ColorReset)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// historyEntry is an item read or fetched, for "adncli history".
+type historyEntry struct {
+	title    string
+	link     string
+	category string
+	when     time.Time
+	opened   bool
+}
+
+// parseSince reads a look-back period: a number of days such as "7d", or
+// a Go duration such as "12h".
+func parseSince(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("periodo %q non valido", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("periodo %q non valido, usa per esempio 7d o 12h", s)
+	}
+	return d, nil
+}
+
+// history returns the items opened, and unless openedOnly those fetched,
+// since the given time in the given categories (all when empty), newest
+// first. An item both fetched and opened is listed once, as opened.
+func history(since time.Time, categories []string, term string, openedOnly bool) ([]historyEntry, error) {
+	inCategory := func(name string) bool {
+		if len(categories) == 0 {
+			return true
+		}
+		for _, c := range categories {
+			if strings.EqualFold(c, name) {
+				return true
+			}
+		}
+		return false
+	}
+	matches := func(title string) bool {
+		return term == "" || strings.Contains(strings.ToLower(title), strings.ToLower(term))
+	}
+
+	opened, err := loadOpened()
+	if err != nil {
+		return nil, err
+	}
+	var entries []historyEntry
+	read := make(map[string]bool)
+	for _, it := range opened {
+		if it.Opened.Before(since) || !inCategory(it.Category) || !matches(it.Title) {
+			continue
+		}
+		entries = append(entries, historyEntry{it.Title, it.Link, it.Category, it.Opened, true})
+		read[it.Link] = true
+	}
+
+	if !openedOnly {
+		fetched, err := archivedItems(archiveQuery{categories: categories, term: term, since: since})
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range fetched {
+			if read[it.Link] {
+				continue
+			}
+			entries = append(entries, historyEntry{strings.TrimSpace(it.Title), it.Link, it.Source, it.firstSeen, false})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].when.After(entries[j].when)
+	})
+	return entries, nil
+}
+
+// runHistory implements "adncli history [--since 7d] [--category C] [--opened] [termine]".
+func (r *RssReader) runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	sinceFlag := fs.String("since", "7d", "periodo da mostrare, come 7d o 12h")
+	only := fs.String("category", "", "categorie da mostrare, separate da virgola (predefinito: tutte)")
+	openedOnly := fs.Bool("opened", false, "mostra solo le notizie aperte, non quelle soltanto scaricate")
+	words, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	period, err := parseSince(*sinceFlag)
+	if err != nil {
+		return err
+	}
+
+	var categories []string
+	if *only != "" {
+		for _, key := range strings.Split(*only, ",") {
+			cat, ok := r.findCategory(key)
+			if !ok {
+				return fmt.Errorf("categoria %q sconosciuta", strings.TrimSpace(key))
+			}
+			categories = append(categories, cat.Name)
+		}
+	}
+
+	term := strings.TrimSpace(strings.Join(words, " "))
+	entries, err := history(time.Now().Add(-period), categories, term, *openedOnly)
+	if err != nil {
+		return err
+	}
+
+	plainIfPiped()
+	w := r.out.content
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "Nessuna notizia negli ultimi %s.\n", *sinceFlag)
+		return nil
+	}
+	for i, e := range entries {
+		action := "scaricata"
+		if e.opened {
+			action = "letta"
+		}
+		meta := []string{action + " " + dates.short(e.when)}
+		if e.category != "" {
+			meta = append([]string{e.category}, meta...)
+		}
+		fmt.Fprintf(w, "%s[%d]%s %s%s%s\n", ColorBlue, i+1, ColorReset, ColorBold, e.title, ColorReset)
+		fmt.Fprintf(w, "    %s%s%s\n    %s\n", ColorCyan, strings.Join(meta, " · "), ColorReset, e.link)
+	}
+	return nil
+}
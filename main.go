@@ -8,18 +8,30 @@ import (
 	"bufio"
 	"context"
 	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
-	"html"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
 	"regexp"
+	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+
+	"github.com/skip2/go-qrcode"
 )
 
 // --- ANSI Color Codes ---
-const (
+// These are variables so that disableColors can blank them out.
+var (
 	ColorReset  = "\033[0m"
 	ColorRed    = "\033[31m"
 	ColorGreen  = "\033[32m"
@@ -31,6 +43,34 @@ const (
 	ColorBold   = "\033[1m"
 )
 
+// colors lists the color variables.
+var colors = []*string{
+	&ColorReset, &ColorRed, &ColorGreen, &ColorYellow, &ColorBlue,
+	&ColorPurple, &ColorCyan, &ColorWhite, &ColorBold,
+}
+
+// disableColors turns every color code into an empty string.
+func disableColors() {
+	for _, c := range colors {
+		*c = ""
+	}
+}
+
+// withoutColors runs fn with colors disabled, restoring them afterwards.
+func withoutColors(fn func()) {
+	saved := make([]string, len(colors))
+	for i, c := range colors {
+		saved[i] = *c
+	}
+	defer func() {
+		for i, c := range colors {
+			*c = saved[i]
+		}
+	}()
+	disableColors()
+	fn()
+}
+
 // Rss represents the root <rss> element.
 type Rss struct {
 	Channel Channel `xml:"channel"`
@@ -38,18 +78,39 @@ type Rss struct {
 
 // Channel represents the <channel> section of an RSS feed.
 type Channel struct {
-	Title       string `xml:"title"`
-	Description string `xml:"description"`
-	Link        string `xml:"link"`
-	Items       []Item `xml:"item"`
+	Title         string `xml:"title"`
+	Description   string `xml:"description"`
+	Link          string `xml:"link"`
+	Language      string `xml:"language"`
+	LastBuildDate string `xml:"lastBuildDate"`
+	Generator     string `xml:"generator"`
+	Copyright     string `xml:"copyright"`
+	Image         *Image `xml:"image"`
+	Items         []Item `xml:"item"`
+}
+
+// Image represents the optional <image> element of a channel.
+type Image struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
 }
 
 // Item represents a single <item> entry.
 type Item struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	GUID        string   `xml:"guid"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+
+	// Source is the category the item was merged from in the aggregated
+	// view; it is empty in a single feed.
+	Source string `xml:"-"`
+
+	// Raw is the XML element the item was decoded from, for "raw".
+	Raw []byte `xml:"-"`
 }
 
 // FeedCategory holds the metadata for a selectable RSS category.
@@ -63,11 +124,88 @@ type FeedCategory struct {
 type RssReader struct {
 	categories   []FeedCategory
 	htmlTagRegex *regexp.Regexp
-	client       *http.Client
+	pipeline     []textStage
+	// categoryPipelines replaces pipeline for categories with their own
+	// boilerplate rules, by category ID.
+	categoryPipelines map[int][]textStage
+	client            *http.Client
+	fetcher           Fetcher
+	timeout           time.Duration
+	linkTemplate      string
+
+	// footer is the attribution template of org and Markdown output.
+	footer string
+
+	// defaultCategory, if set, is loaded as soon as the menu starts: the
+	// category viewed last with --resume, else default_category.
+	defaultCategory *FeedCategory
+
+	// current is the last feed rendered, used by commands such as info,
+	// and currentFeed the category it was fetched from.
+	current     *Rss
+	currentFeed FeedCategory
+
+	// output selects how feeds are rendered: "text", "table", "org" or
+	// "obsidian".
+	output string
+
+	// sortMenu orders the menu: "id" (default) or "usage".
+	sortMenu string
+
+	// width forces the layout width; 0 means detect it from the terminal.
+	width int
+
+	// hideDesc renders titles only.
+	hideDesc bool
+
+	// attached, if set, is the adncli serve that fetches the feeds and
+	// keeps the queue in place of the reader.
+	attached *attachClient
+
+	// parsed keeps the feeds decoded in this session, so that a 304 Not
+	// Modified is answered without reading the cached body again.
+	parsedMu sync.Mutex
+	parsed   map[string]parsedFeed
+
+	// prefs are per-category overrides of output and hideDesc, whose
+	// general values are kept in baseOutput and baseHideDesc.
+	prefs        map[int]categoryPrefs
+	baseOutput   string
+	baseHideDesc bool
+
+	// mirrors are the fallback URLs of a feed, keyed by its primary URL
+	// and tried in order when it fails.
+	mirrors map[string][]string
+
+	// retention bounds the item archive.
+	retention retention
+
+	// hideFailing, if set, hides the categories failing for longer.
+	hideFailing time.Duration
+
+	// cacheTTL is how long a fetched feed is reused at least, whatever
+	// the server's Cache-Control says.
+	cacheTTL time.Duration
+
+	// tagFilter, when set, restricts the current feed to items carrying
+	// this tag (in tagSlug form).
+	tagFilter string
+
+	// out routes content and diagnostics to their streams.
+	out outputRouter
+
+	// stats counts the activity of the interactive session; hideStats
+	// suppresses the summary printed on exit.
+	stats     sessionStats
+	hideStats bool
+
+	// screenReader switches to linear, label-first output with verbose
+	// announcements of context changes.
+	screenReader bool
 }
 
 // NewRssReader initializes the reader with configuration and compiled regex.
-func NewRssReader() (*RssReader, error) {
+func NewRssReader(cfg Config) (*RssReader, error) {
 	categories := []FeedCategory{
 		{1, "Prima Pagina", "https://www.adnkronos.com/RSS_PrimaPagina.xml"},
 		{2, "Ultim'ora", "https://www.adnkronos.com/RSS_Ultimora.xml"},
@@ -79,102 +217,966 @@ func NewRssReader() (*RssReader, error) {
 		{8, "Sport", "https://www.adnkronos.com/RSS_Sport.xml"},
 	}
 
-	re, err := regexp.Compile(`<[^>]*>`)
+	transport := newTransport(cfg.DialTimeout, cfg.TLSTimeout, cfg.HeaderTimeout)
+	r := &RssReader{
+		categories:   categories,
+		htmlTagRegex: htmlTag,
+		client:       &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		timeout:      cfg.Timeout,
+		linkTemplate: cfg.LinkTemplate,
+		footer:       cfg.Attribution,
+		retention:    retention{cfg.ArchiveDays, cfg.ArchiveItems},
+		hideFailing:  time.Duration(cfg.HideFailingDays) * 24 * time.Hour,
+		cacheTTL:     cfg.CacheTTL,
+		out:          newOutputRouter(true),
+		output:       cfg.Output,
+		sortMenu:     cfg.SortMenu,
+		width:        cfg.Width,
+		hideDesc:     cfg.NoDesc,
+		baseOutput:   cfg.Output,
+		baseHideDesc: cfg.NoDesc,
+		hideStats:    cfg.NoStats,
+		screenReader: cfg.ScreenReader,
+	}
+	r.fetcher = chain(r.client, withUserAgent(userAgent))
+	if cfg.Verbose {
+		r.use(withLogging(os.Stderr))
+	}
+
+	if cfg.Proxy != "" {
+		proxy, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		r.useProxy(proxy)
+	}
+
+	stages := strings.Split(cfg.Clean, ",")
+	if cfg.NoAlt {
+		stages = slices.DeleteFunc(stages, func(s string) bool { return strings.TrimSpace(s) == "alt" })
+	}
+	rules := append(append([]string{}, defaultBoilerplate...), cfg.Boilerplate...)
+	if err := r.setCleanPipeline(stages, rules); err != nil {
+		return nil, err
+	}
+
+	// An attached reader shows the categories of the server, custom feeds
+	// included.
+	if cfg.Attach != "" {
+		if err := r.attach(cfg.Attach, cfg.AttachToken); err != nil {
+			return nil, err
+		}
+	} else if err := r.addConfigFeeds(cfg.Feeds); err != nil {
+		return nil, err
+	}
+	if err := r.setCategoryPrefs(cfg.Categories); err != nil {
+		return nil, err
+	}
+	if cfg.DefaultCategory != "" {
+		cat, ok := r.findCategory(cfg.DefaultCategory)
+		if !ok {
+			return nil, fmt.Errorf("categoria predefinita %q sconosciuta", cfg.DefaultCategory)
+		}
+		r.defaultCategory = &cat
+	}
+	if cfg.Resume {
+		if cat, ok := lastViewed(r.categories); ok {
+			r.defaultCategory = &cat
+		}
+	}
+
+	return r, nil
+}
+
+// fetchFeed downloads and parses the RSS. The validators of the last
+// response are sent along, and a 304 Not Modified is served from the body
+// cached on disk, so that even one-shot runs save bandwidth. When the
+// feed cannot be fetched, its mirrors are tried in order. What the fetch
+// changes in the feed status is written once, at the end.
+func (r *RssReader) fetchFeed(ctx context.Context, url string) (*Rss, error) {
+	rss, persist, err := r.fetchUnsaved(ctx, url)
+	persist()
+	return rss, err
+}
+
+// fetchUnsaved is fetchFeed leaving the writes of the feed status and of
+// the archive to persist, which the caller must run, for callers that
+// answer before touching the disk. Until then loadFeedStates already
+// reports the fetch.
+func (r *RssReader) fetchUnsaved(ctx context.Context, url string) (rss *Rss, persist func(), err error) {
+	if r.attached != nil {
+		rss, err := r.attachedFeed(ctx, url)
+		return rss, flushFeedStates, err
+	}
+	rss, source, err := r.fetchMirrored(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compile regex: %w", err)
+		// Neither giving up nor running out of the status line budget
+		// says anything about the feed.
+		if !errors.Is(err, context.Canceled) && !errors.Is(context.Cause(ctx), errOverBudget) {
+			recordFailure(url)
+		}
+		return nil, flushFeedStates, err
 	}
 
-	return &RssReader{
-		categories:   categories,
-		htmlTagRegex: re,
-		client:       &http.Client{Timeout: 10 * time.Second},
+	recordFetch(url, source, rss)
+	return rss, func() {
+		flushFeedStates()
+		r.archiveItems(url, rss)
 	}, nil
 }
 
-// cleanText removes HTML tags and unescapes HTML entities.
-func (r *RssReader) cleanText(text string) string {
-	clean := r.htmlTagRegex.ReplaceAllString(text, "")
-	clean = html.UnescapeString(clean)
-	return strings.TrimSpace(clean)
+// fetchSource fetches the feed from one of its sources, the primary URL
+// or a mirror, each with its own validators and cached body. A body still
+// fresh is served without asking the server.
+func (r *RssReader) fetchSource(ctx context.Context, url string) (*Rss, error) {
+	if rss, ok := r.freshCached(url); ok {
+		return rss, nil
+	}
+	saved := savedValidators(url)
+	rss, err := r.fetchFeedWith(ctx, url, saved)
+	if err != nil && saved != (validators{}) && errors.Is(err, errBadCache) {
+		// The cached body no longer decodes: start over without it.
+		forgetValidators(url)
+		rss, err = r.fetchFeedWith(ctx, url, validators{})
+	}
+	return rss, err
 }
 
-// fetchFeed downloads and parses the RSS.
-func (r *RssReader) fetchFeed(ctx context.Context, url string) (*Rss, error) {
+// errBadCache is returned when a 304 response leads to a cached body that
+// cannot be decoded.
+var errBadCache = errors.New("cached feed is unreadable")
+
+// fetchFeedWith performs one request for url with the given validators.
+func (r *RssReader) fetchFeedWith(ctx context.Context, url string, v validators) (*Rss, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
 
-	req.Header.Set("User-Agent", "Adnkronos-CLI-Reader/1.0")
-
-	resp, err := r.client.Do(req)
+	resp, err := r.fetcher.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	lifetime := freshness(resp.Header, r.cacheTTL)
+	if resp.StatusCode == http.StatusNotModified && v != (validators{}) {
+		rss, err := r.decodeCached(url, v)
+		if err == nil && lifetime > 0 {
+			saveFreshUntil(url, time.Now().Add(lifetime))
+		}
+		return rss, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if wait := retryAfter(resp.Header.Get("Retry-After"), time.Now()); wait > 0 {
+			return nil, fmt.Errorf("HTTP error: %s, retry after %s", resp.Status, wait)
+		}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("HTTP error: %d %s", resp.StatusCode, resp.Status)
 	}
 
+	// Bodies are only worth caching when the server sent validators or
+	// they may be reused for a while.
+	fresh := validators{resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")}
+	var cache *cacheWriter
+	if fresh != (validators{}) || lifetime > 0 {
+		cache = newCacheWriter(url)
+	}
+	rss, err := decodeFeed(cache.tee(resp.Body), resp.Header.Get("Content-Type"))
+	if cerr := cache.commit(resp.Body, err != nil); cerr == nil && err == nil && cache != nil {
+		saveValidators(url, fresh)
+		var until time.Time
+		if lifetime > 0 {
+			until = time.Now().Add(lifetime)
+		}
+		saveFreshUntil(url, until)
+		r.rememberParsed(url, fresh, rss)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("feed decode error: %w", err)
+	}
+	return rss, nil
+}
+
+// retryAfter interprets a Retry-After header, which is either a number of
+// seconds or an HTTP date. It returns 0 when the header is missing or invalid.
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now).Round(time.Second)
+	}
+	return 0
+}
+
+// normalizeXML drops a UTF-8 byte order mark and any whitespace or stray
+// bytes before the first '<', which encoding/xml otherwise rejects.
+func normalizeXML(body io.Reader) io.Reader {
+	br := bufio.NewReader(body)
+	for {
+		b, err := br.Peek(1)
+		if err != nil || b[0] == '<' {
+			return br
+		}
+		br.Discard(1)
+	}
+}
+
+// decodeRss walks the XML tokens of the channel and decodes one <item> at
+// a time, skipping unknown elements and keeping the source of each item
+// for "raw". Every item is returned at the end: nothing is shown before
+// the whole feed is read, and memory grows with the feed and its sources.
+func decodeRss(body io.Reader) (*Rss, error) {
+	rec := &rawRecorder{r: body}
+	dec := xml.NewDecoder(rec)
+
 	var rss Rss
-	if err := xml.NewDecoder(resp.Body).Decode(&rss); err != nil {
-		return nil, fmt.Errorf("xml decode error: %w", err)
+	inChannel := false
+	for {
+		start := dec.InputOffset()
+		rec.mark(start)
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if !inChannel {
+				inChannel = t.Name.Local == "channel"
+				continue
+			}
+
+			var err error
+			switch t.Name.Local {
+			case "item":
+				var item Item
+				if err = dec.DecodeElement(&item, &t); err == nil {
+					item.Raw = rec.slice(start, dec.InputOffset())
+					rss.Channel.Items = append(rss.Channel.Items, item)
+				}
+			case "title":
+				err = dec.DecodeElement(&rss.Channel.Title, &t)
+			case "description":
+				err = dec.DecodeElement(&rss.Channel.Description, &t)
+			case "link":
+				// Skip <atom:link rel="self"> which many feeds add next
+				// to the plain RSS link.
+				if t.Name.Space != "" {
+					err = dec.Skip()
+					break
+				}
+				err = dec.DecodeElement(&rss.Channel.Link, &t)
+			case "language":
+				err = dec.DecodeElement(&rss.Channel.Language, &t)
+			case "lastBuildDate":
+				err = dec.DecodeElement(&rss.Channel.LastBuildDate, &t)
+			case "generator":
+				err = dec.DecodeElement(&rss.Channel.Generator, &t)
+			case "copyright", "rights":
+				// <dc:rights> stands in for <copyright> in some feeds.
+				err = dec.DecodeElement(&rss.Channel.Copyright, &t)
+			case "image":
+				var img Image
+				if err = dec.DecodeElement(&img, &t); err == nil {
+					rss.Channel.Image = &img
+				}
+			default:
+				err = dec.Skip()
+			}
+			if err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if t.Name.Local == "channel" {
+				return &rss, nil
+			}
+		}
 	}
 
+	if !inChannel {
+		return nil, fmt.Errorf("missing <channel> element")
+	}
 	return &rss, nil
 }
 
+// pubDateLayouts lists the date formats seen in the wild in <pubDate>.
+var pubDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+// parsePubDate parses an RSS date, reporting whether any known layout matched.
+func parsePubDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range pubDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// findCategory looks a category up by ID or by name, ignoring case, spaces
+// and punctuation ("ultimora" matches "Ultim'ora").
+func (r *RssReader) findCategory(key string) (FeedCategory, bool) {
+	normalize := func(s string) string {
+		return strings.Map(func(c rune) rune {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				return unicode.ToLower(c)
+			}
+			return -1
+		}, s)
+	}
+
+	key = normalize(key)
+	for _, cat := range r.categories {
+		if strconv.Itoa(cat.ID) == key || normalize(cat.Name) == key {
+			return cat, true
+		}
+	}
+	return FeedCategory{}, false
+}
+
+// categoryByURL returns the category whose feed is at url.
+func (r *RssReader) categoryByURL(url string) (FeedCategory, bool) {
+	for _, cat := range r.categories {
+		if cat.URL == url {
+			return cat, true
+		}
+	}
+	return FeedCategory{}, false
+}
+
+// menuCategories returns the categories in menu order. With sortMenu set
+// to "usage" the most selected come first; IDs never change, so a number
+// always selects the same category whatever its position. Categories
+// hidden for failing are left out.
+func (r *RssReader) menuCategories() []FeedCategory {
+	states, err := loadFeedStates()
+	if err != nil {
+		return r.categories
+	}
+	shown := r.activeCategories(states)
+	if r.sortMenu != "usage" {
+		return shown
+	}
+
+	sorted := append([]FeedCategory(nil), shown...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return states.get(sorted[i].URL).Selections > states.get(sorted[j].URL).Selections
+	})
+	return sorted
+}
+
 // printMenu dynamically prints options based on the categories slice.
 func (r *RssReader) printMenu() {
+	if r.screenReader {
+		r.printMenuAccessible()
+		return
+	}
+
 	// Header in Bold Cyan
-	fmt.Printf("\n%s--- Adnkronos RSS Reader ---%s\n", ColorBold+ColorCyan, ColorReset)
+	fmt.Fprintf(r.out.diag, "\n%s--- Adnkronos RSS Reader ---%s\n", ColorBold+ColorCyan, ColorReset)
 
 	// Option 0 in Red
-	fmt.Printf("%s0:%s Esci\n", ColorRed, ColorReset)
+	fmt.Fprintf(r.out.diag, "%s0:%s Esci\n", ColorRed, ColorReset)
 
-	for _, cat := range r.categories {
+	shown := r.menuCategories()
+	for _, cat := range shown {
 		// ID in Yellow, Name in standard color
-		fmt.Printf("%s%d:%s %s\n", ColorYellow, cat.ID, ColorReset, cat.Name)
+		fmt.Fprintf(r.out.diag, "%s%d:%s %s\n", ColorYellow, cat.ID, ColorReset, cat.Name)
+	}
+	fmt.Fprintf(r.out.diag, "%sa:%s %s\n", ColorYellow, ColorReset, allTitle)
+	if hidden := len(r.categories) - len(shown); hidden > 0 {
+		fmt.Fprintf(r.out.diag, "%s(%d categorie nascoste perché non funzionano: adncli feed list --all)%s\n", ColorRed, hidden, ColorReset)
 	}
+
+	if r.current != nil {
+		r.printCommands()
+	}
+	fmt.Fprintf(r.out.diag, "%shelp:%s Elenco dei comandi\n", ColorYellow, ColorReset)
 	// Prompt in Bold
-	fmt.Printf("\n%sSeleziona un numero: %s", ColorBold, ColorReset)
+	fmt.Fprintf(r.out.diag, "\n%sSeleziona un numero: %s", ColorBold, ColorReset)
+}
+
+// printMenuAccessible prints the menu as plain labelled lines.
+func (r *RssReader) printMenuAccessible() {
+	shown := r.menuCategories()
+	fmt.Fprintf(r.out.diag, "\nMenu principale, %d categorie.\n", len(shown))
+	fmt.Fprintln(r.out.diag, "Opzione 0: Esci.")
+	for _, cat := range shown {
+		fmt.Fprintf(r.out.diag, "Opzione %d: %s.\n", cat.ID, cat.Name)
+	}
+	fmt.Fprintf(r.out.diag, "Opzione a: %s.\n", allTitle)
+	if hidden := len(r.categories) - len(shown); hidden > 0 {
+		fmt.Fprintf(r.out.diag, "Categorie nascoste perché non funzionano: %d. Per vederle: adncli feed list --all.\n", hidden)
+	}
+	if r.current != nil {
+		r.printCommands()
+	}
+	fmt.Fprintln(r.out.diag, "Comando help: Elenco dei comandi.")
+	fmt.Fprint(r.out.diag, "Seleziona un numero: ")
+}
+
+// printCommands lists the commands acting on the feed shown last.
+func (r *RssReader) printCommands() {
+	if r.screenReader {
+		fmt.Fprintln(r.out.diag, "Comando info: Dettagli del feed corrente.")
+		fmt.Fprintln(r.out.diag, "Comando open seguito dal numero: Apri la notizia nel browser.")
+		fmt.Fprintln(r.out.diag, "Comando queue seguito dal numero: Metti la notizia nella coda da leggere.")
+		fmt.Fprintln(r.out.diag, "Comando s seguito dal numero: Salva la notizia tra i preferiti.")
+		fmt.Fprintln(r.out.diag, "Comando d: Mostra o nasconde le descrizioni.")
+		fmt.Fprintln(r.out.diag, "Comando tag seguito dal nome: Filtra le notizie per tag.")
+		fmt.Fprintln(r.out.diag, "Comando mail seguito dal numero: Condividi la notizia via email.")
+		fmt.Fprintln(r.out.diag, "Comando qr seguito dal numero: Mostra il link come codice QR.")
+		fmt.Fprintln(r.out.diag, "Comando peek seguito dal numero: Anteprima della pagina della notizia.")
+		fmt.Fprintln(r.out.diag, "Comando raw seguito dal numero: Mostra l'XML originale della notizia.")
+		fmt.Fprintln(r.out.diag, "Comando task seguito dal numero: Crea un'attività dalla notizia.")
+		fmt.Fprintln(r.out.diag, "Comando md seguito dal numero: Copia il link della notizia in formato Markdown.")
+		fmt.Fprintln(r.out.diag, "Comando why seguito dal numero: Spiega filtri e pulizia applicati alla notizia.")
+		fmt.Fprintln(r.out.diag, "Comando export seguito da formato e file: Salva la vista corrente in un file.")
+		return
+	}
+
+	fmt.Fprintf(r.out.diag, "%sinfo:%s Dettagli del feed corrente\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%sopen N:%s Apri la notizia N nel browser\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%squeue N:%s Metti la notizia N nella coda da leggere\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%ss N:%s Salva la notizia N tra i preferiti (adncli starred per rivederle)\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%sd:%s Mostra/nascondi le descrizioni\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%stag T:%s Filtra per tag (tag senza argomenti rimuove il filtro)\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%smail N:%s Condividi la notizia N via email\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%sqr N:%s Mostra il link della notizia N come codice QR\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%speek N:%s Anteprima della pagina della notizia N\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%sraw N:%s Mostra l'XML originale della notizia N\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%stask N:%s Crea un'attività (Todoist o Taskwarrior) dalla notizia N\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%smd N:%s Copia negli appunti il link Markdown della notizia N\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%swhy N:%s Spiega filtri e pulizia applicati alla notizia N\n", ColorYellow, ColorReset)
+	fmt.Fprintf(r.out.diag, "%sexport F FILE:%s Salva la vista corrente in FILE (md, text, table, org, obsidian)\n", ColorYellow, ColorReset)
+}
+
+// printHelp lists every command, whether or not a feed is loaded.
+func (r *RssReader) printHelp() {
+	if r.screenReader {
+		fmt.Fprintf(r.out.diag, "Digita un numero da 1 a %d per scegliere una categoria, a per tutte, 0 per uscire.\n", len(r.categories))
+		fmt.Fprintln(r.out.diag, "Comando help: Mostra questo elenco.")
+	} else {
+		fmt.Fprintf(r.out.diag, "\n%s1-%d:%s Scegli una categoria, %sa:%s tutte le notizie, %s0:%s esci\n",
+			ColorYellow, len(r.categories), ColorReset, ColorYellow, ColorReset, ColorYellow, ColorReset)
+		fmt.Fprintf(r.out.diag, "%shelp:%s Mostra questo elenco\n", ColorYellow, ColorReset)
+	}
+	r.printCommands()
+	if r.current == nil {
+		fmt.Fprintln(r.out.diag, "I comandi sulle notizie richiedono un feed caricato.")
+	}
 }
 
 // displayFeed renders the feed items to stdout.
 func (r *RssReader) displayFeed(rss *Rss) {
+	if r.screenReader {
+		r.displayFeedAccessible(rss)
+		return
+	}
+	switch r.output {
+	case "table":
+		r.displayTable(rss)
+		return
+	case "org":
+		r.displayOrg(rss)
+		return
+	case "obsidian":
+		r.displayObsidian(rss)
+		return
+	}
+
+	title := strings.TrimSpace(rss.Channel.Title)
+	if title == "" {
+		title = r.currentFeed.Name
+	}
+
 	// Channel Title in Bold Green background or just Bold Green text
-	fmt.Printf("\n%s=== %s ===%s\n", ColorBold+ColorGreen, strings.ToUpper(rss.Channel.Title), ColorReset)
-	fmt.Printf("%s%s%s\n\n", ColorPurple, rss.Channel.Description, ColorReset)
+	fmt.Fprintf(r.out.content, "\n%s=== %s ===%s\n", ColorBold+ColorGreen, strings.ToUpper(title), ColorReset)
+	if desc := strings.TrimSpace(rss.Channel.Description); desc != "" {
+		fmt.Fprintf(r.out.content, "%s%s%s\n", ColorPurple, desc, ColorReset)
+	}
+	fmt.Fprintln(r.out.content)
 
 	if len(rss.Channel.Items) == 0 {
-		fmt.Println("Nessuna notizia trovata in questo feed.")
+		r.displayEmptyState()
 		return
 	}
 
+	if r.tagFilter != "" {
+		fmt.Fprintf(r.out.content, "%sFiltro tag: %s%s\n\n", ColorYellow, r.tagFilter, ColorReset)
+		if shown, _ := r.countVisible(rss); shown == 0 {
+			fmt.Fprintln(r.out.content, "Nessuna notizia con questo tag.")
+			return
+		}
+	}
+
 	for i, item := range rss.Channel.Items {
-		// Index in Blue, Title in Bold White
-		fmt.Printf("%s[%d]%s %s%s%s\n", ColorBlue, i+1, ColorReset, ColorBold, strings.TrimSpace(item.Title), ColorReset)
+		if !r.visible(item) {
+			continue
+		}
+
+		// Index in Blue, Source in Yellow, Title in Bold White
+		source := ""
+		if item.Source != "" {
+			source = fmt.Sprintf("%s[%s]%s ", ColorYellow, item.Source, ColorReset)
+		}
+		fmt.Fprintf(r.out.content, "%s[%d]%s %s%s%s%s\n", ColorBlue, i+1, ColorReset, source, ColorBold, strings.TrimSpace(item.Title), ColorReset)
 
 		if item.PubDate != "" {
 			// Date in Cyan
-			fmt.Printf("    Pubblicato: %s%s%s\n", ColorCyan, item.PubDate, ColorReset)
+			fmt.Fprintf(r.out.content, "    Pubblicato: %s%s%s\n", ColorCyan, pubDate(item), ColorReset)
+		}
+
+		if len(item.Categories) > 0 {
+			fmt.Fprintf(r.out.content, "    Tag: %s%s%s\n", ColorYellow, strings.Join(item.Categories, ", "), ColorReset)
 		}
 
-		desc := r.cleanText(item.Description)
-		if desc != "" {
-			fmt.Printf("    %s\n", desc)
+		if !r.hideDesc {
+			if desc := r.cleanText(r.sourceOf(item), item.Description); desc != "" {
+				fmt.Fprintf(r.out.content, "    %s\n", desc)
+			}
 		}
 
 		// Separator in faint gray (using standard here for compatibility)
-		fmt.Println(strings.Repeat("-", 60))
+		fmt.Fprintln(r.out.content, strings.Repeat("-", min(60, r.layoutWidth())))
 	}
 }
 
+// tagSlug normalizes a tag for comparison: "Sport Calcio" becomes "sport-calcio".
+func tagSlug(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
+
+// visible reports whether item passes the active filters.
+func (r *RssReader) visible(item Item) bool {
+	if r.tagFilter == "" {
+		return true
+	}
+	for _, tag := range item.Categories {
+		if tagSlug(tag) == r.tagFilter {
+			return true
+		}
+	}
+	return false
+}
+
+// countVisible returns how many items of rss are shown and hidden by filters.
+func (r *RssReader) countVisible(rss *Rss) (shown, hidden int) {
+	for _, item := range rss.Channel.Items {
+		if r.visible(item) {
+			shown++
+		} else {
+			hidden++
+		}
+	}
+	return shown, hidden
+}
+
+// displayEmptyState explains an empty feed, recalling when it last had
+// news.
+func (r *RssReader) displayEmptyState() {
+	fmt.Fprintln(r.out.content, "Il feed al momento non contiene notizie.")
+
+	states, err := loadFeedStates()
+	if err != nil {
+		return
+	}
+	st, ok := states.Feeds[r.currentFeed.URL]
+	if !ok || st.LastSuccess.IsZero() {
+		fmt.Fprintln(r.out.content, "Non risultano scaricamenti precedenti con notizie.")
+		return
+	}
+
+	fmt.Fprintf(r.out.content, "%sUltimo scaricamento con notizie:%s %s (%d notizie)\n",
+		ColorCyan, ColorReset, dates.long(st.LastSuccess), st.LastItems)
+	if st.LastHeadline != "" {
+		fmt.Fprintf(r.out.content, "%sUltimo titolo:%s %s\n", ColorCyan, ColorReset, strings.TrimSpace(st.LastHeadline))
+	}
+}
+
+// displayFeedAccessible renders the feed with labels before values and
+// no decorative separators.
+func (r *RssReader) displayFeedAccessible(rss *Rss) {
+	shown, _ := r.countVisible(rss)
+	fmt.Fprintf(r.out.content, "\nElenco notizie, %d elementi. Feed: %s.\n", shown, strings.TrimSpace(rss.Channel.Title))
+	if desc := strings.TrimSpace(rss.Channel.Description); desc != "" {
+		fmt.Fprintf(r.out.content, "Descrizione del feed: %s\n", desc)
+	}
+	if r.tagFilter != "" {
+		fmt.Fprintf(r.out.content, "Filtro tag attivo: %s.\n", r.tagFilter)
+	}
+
+	for i, item := range rss.Channel.Items {
+		if !r.visible(item) {
+			continue
+		}
+
+		fmt.Fprintf(r.out.content, "\nNotizia numero %d.\n", i+1)
+		if item.Source != "" {
+			fmt.Fprintf(r.out.content, "Categoria: %s\n", item.Source)
+		}
+		fmt.Fprintf(r.out.content, "Titolo: %s\n", strings.TrimSpace(item.Title))
+		if item.PubDate != "" {
+			fmt.Fprintf(r.out.content, "Pubblicato: %s\n", pubDate(item))
+		}
+		if len(item.Categories) > 0 {
+			fmt.Fprintf(r.out.content, "Tag: %s\n", strings.Join(item.Categories, ", "))
+		}
+		if desc := r.cleanText(r.sourceOf(item), item.Description); desc != "" && !r.hideDesc {
+			fmt.Fprintf(r.out.content, "Descrizione: %s\n", desc)
+		}
+	}
+	fmt.Fprintln(r.out.content, "\nFine elenco notizie.")
+}
+
+// displayFooter summarizes the feed just rendered.
+func (r *RssReader) displayFooter(rss *Rss, elapsed time.Duration) {
+	var newest, oldest time.Time
+	for _, item := range rss.Channel.Items {
+		if !r.visible(item) {
+			continue
+		}
+		t, ok := parsePubDate(item.PubDate)
+		if !ok {
+			continue
+		}
+		if newest.IsZero() || t.After(newest) {
+			newest = t
+		}
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+
+	shown, hidden := r.countVisible(rss)
+	fmt.Fprintf(r.out.diag, "%s%d notizie mostrate", ColorPurple, shown)
+	if hidden > 0 {
+		fmt.Fprintf(r.out.diag, ", %d nascoste dai filtri", hidden)
+	}
+	if !newest.IsZero() {
+		fmt.Fprintf(r.out.diag, " | più recente: %s | meno recente: %s",
+			dates.short(newest), dates.short(oldest))
+	}
+	fmt.Fprintf(r.out.diag, " | scaricate in %s%s\n", elapsed.Round(time.Millisecond), ColorReset)
+	if factor, recent, ok := volumeSpike(r.currentFeed.URL); ok {
+		fmt.Fprintf(r.out.diag, "%sVolume %.0fx sopra la media: %d notizie nell'ultima ora%s\n",
+			ColorRed, factor, recent, ColorReset)
+	}
+}
+
+// displayInfo renders the metadata of the channel shown last.
+func (r *RssReader) displayInfo() {
+	if r.current == nil {
+		r.reportError(errNoFeed)
+		return
+	}
+
+	ch := r.current.Channel
+	if r.screenReader {
+		fmt.Fprintf(r.out.content, "\nDettagli del feed: %s.\n", strings.TrimSpace(ch.Title))
+	} else {
+		fmt.Fprintf(r.out.content, "\n%s=== %s ===%s\n", ColorBold+ColorGreen, strings.ToUpper(ch.Title), ColorReset)
+	}
+
+	fields := [][2]string{
+		{"Descrizione", ch.Description},
+		{"Link", ch.Link},
+		{"Lingua", ch.Language},
+		{"Ultimo aggiornamento", ch.LastBuildDate},
+		{"Generatore", ch.Generator},
+		{"Copyright", ch.Copyright},
+	}
+	if ch.Image != nil {
+		fields = append(fields, [2]string{"Immagine", ch.Image.URL})
+	}
+	if states, err := loadFeedStates(); err == nil {
+		if st, ok := states.Feeds[r.currentFeed.URL]; ok && st.LastSource != "" {
+			fields = append(fields, [2]string{"Servito dal mirror", st.LastSource})
+		}
+	}
+
+	for _, f := range fields {
+		value := strings.TrimSpace(f[1])
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(r.out.content, "%s%s:%s %s\n", ColorCyan, f[0], ColorReset, value)
+	}
+	fmt.Fprintf(r.out.content, "%sNotizie:%s %d\n", ColorCyan, ColorReset, len(ch.Items))
+}
+
+// itemAt resolves a 1-based item number typed by the user against the
+// feed shown last.
+func (r *RssReader) itemAt(arg string) (*Item, error) {
+	if r.current == nil {
+		return nil, errNoFeed
+	}
+
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(r.current.Channel.Items) {
+		return nil, &itemError{arg: arg, count: len(r.current.Channel.Items)}
+	}
+	return &r.current.Channel.Items[n-1], nil
+}
+
+// mailItem opens the default mail client with a draft sharing the item.
+func (r *RssReader) mailItem(item *Item) error {
+	body := strings.TrimSpace(item.Title) + "\n\n"
+	if desc := r.cleanText(r.sourceOf(*item), item.Description); desc != "" {
+		body += desc + "\n\n"
+	}
+	body += item.Link
+
+	// mailto: wants %20 for spaces, not the '+' produced by QueryEscape.
+	escape := func(s string) string {
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	}
+	link := "mailto:?subject=" + escape(strings.TrimSpace(item.Title)) + "&body=" + escape(body)
+
+	return openURL(link)
+}
+
+// openURL hands a URL to the desktop's default handler.
+func openURL(link string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	return cmd.Start()
+}
+
+// handleCommand runs a textual command typed at the menu prompt.
+// It reports whether the input was recognised as a command.
+func (r *RssReader) handleCommand(fields []string) bool {
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "help":
+		r.printHelp()
+	case "info":
+		r.displayInfo()
+	case "tag":
+		r.tagFilter = ""
+		if len(fields) > 1 {
+			r.tagFilter = tagSlug(strings.Join(fields[1:], " "))
+		}
+		if r.current != nil {
+			r.displayFeed(r.current)
+		}
+	case "d":
+		r.hideDesc = !r.hideDesc
+		if r.current != nil {
+			r.displayFeed(r.current)
+		}
+	case "raw":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "raw <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		if err := r.displayRaw(item); err != nil {
+			r.reportError(err)
+		}
+	case "peek":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "peek <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		r.displayPeek(item)
+	case "md":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "md <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		link := r.itemLink(item)
+		if err := copyToClipboard(link); err != nil {
+			// Without a clipboard the link can still be copied by hand.
+			fmt.Fprintln(r.out.content, link)
+			if !errors.Is(err, errNoClipboard) {
+				fmt.Fprintf(r.out.diag, "%s>> Errore nel copiare negli appunti: %v%s\n", ColorRed, err, ColorReset)
+			}
+			break
+		}
+		fmt.Fprintf(r.out.diag, "%sCopiato negli appunti:%s %s\n", ColorGreen, ColorReset, link)
+	case "export":
+		if len(fields) < 3 {
+			r.reportError(&usageError{usage: "export <formato> <file>"})
+			break
+		}
+		if err := r.exportView(fields[1], strings.Join(fields[2:], " ")); err != nil {
+			r.reportError(err)
+		}
+	case "why":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "why <numero>"})
+			break
+		}
+		if err := r.explainItem(fields[1]); err != nil {
+			r.reportError(err)
+		}
+	case "task":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "task <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		backend, err := r.createTask(item)
+		if err != nil {
+			fmt.Fprintf(r.out.diag, "%s>> Errore nel creare l'attività: %v%s\n", ColorRed, err, ColorReset)
+			break
+		}
+		fmt.Fprintf(r.out.diag, "%sAttività creata in %s.%s\n", ColorGreen, backend, ColorReset)
+		r.stats.itemsOpened++
+	case "open", "queue":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: fields[0] + " <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		if strings.ToLower(fields[0]) == "open" {
+			if err := r.openItem(strings.TrimSpace(item.Title), item.Link, r.sourceOf(*item).Name); err != nil {
+				fmt.Fprintf(r.out.diag, "%s>> Errore nell'aprire la notizia: %v%s\n", ColorRed, err, ColorReset)
+			}
+			break
+		}
+		added, err := r.queueItem(item)
+		switch {
+		case err != nil:
+			fmt.Fprintf(r.out.diag, "%s>> Errore nella coda di lettura: %v%s\n", ColorRed, err, ColorReset)
+		case added:
+			fmt.Fprintf(r.out.diag, "%sAggiunta alla coda di lettura.%s\n", ColorGreen, ColorReset)
+		default:
+			fmt.Fprintln(r.out.diag, "La notizia è già nella coda di lettura.")
+		}
+	case "s":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "s <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		added, err := r.starItem(item)
+		switch {
+		case err != nil:
+			fmt.Fprintf(r.out.diag, "%s>> Errore nel salvare la notizia: %v%s\n", ColorRed, err, ColorReset)
+		case added:
+			fmt.Fprintf(r.out.diag, "%sNotizia salvata tra i preferiti.%s\n", ColorGreen, ColorReset)
+		default:
+			fmt.Fprintln(r.out.diag, "La notizia è già tra i preferiti.")
+		}
+	case "qr":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "qr <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		code, err := qrcode.New(item.Link, qrcode.Medium)
+		if err != nil {
+			fmt.Fprintf(r.out.diag, "%s>> Errore nel generare il codice QR: %v%s\n", ColorRed, err, ColorReset)
+			break
+		}
+		// Light modules are drawn as blocks, which suits dark terminals.
+		fmt.Fprintf(r.out.content, "\n%s%s\n", code.ToSmallString(false), item.Link)
+		r.stats.itemsOpened++
+	case "mail":
+		if len(fields) < 2 {
+			r.reportError(&usageError{usage: "mail <numero>"})
+			break
+		}
+		item, err := r.itemAt(fields[1])
+		if err != nil {
+			r.reportError(err)
+			break
+		}
+		if err := r.mailItem(item); err != nil {
+			fmt.Fprintf(r.out.diag, "%s>> Errore nell'aprire il client di posta: %v%s\n", ColorRed, err, ColorReset)
+			break
+		}
+		r.stats.itemsOpened++
+	default:
+		return false
+	}
+	return true
+}
+
 // Run starts the interactive loop.
 func (r *RssReader) Run() {
 	scanner := bufio.NewScanner(os.Stdin)
 
+	r.stats.started = time.Now()
+	if !r.hideStats {
+		defer r.stats.print(r.out.diag)
+	}
+
+	// Hidden categories that work again show up at a later menu.
+	go r.retryHidden()
+
+	if r.defaultCategory != nil {
+		r.selectCategory(*r.defaultCategory)
+	}
+
 	for {
 		r.printMenu()
 
@@ -183,52 +1185,163 @@ func (r *RssReader) Run() {
 		}
 		input := strings.TrimSpace(scanner.Text())
 
+		if r.handleCommand(strings.Fields(input)) {
+			continue
+		}
+
+		if strings.EqualFold(input, "a") {
+			r.selectAll()
+			continue
+		}
+
 		choice, err := strconv.Atoi(input)
 		if err != nil {
-			// Error in Red
-			fmt.Printf("%s>> Errore: Inserisci un numero valido.%s\n", ColorRed, ColorReset)
+			r.reportError(&choiceError{input})
 			continue
 		}
 
 		if choice == 0 {
-			fmt.Println("Arrivederci!")
+			fmt.Fprintln(r.out.diag, "Arrivederci!")
 			return
 		}
 
-		var selectedURL string
-		for _, cat := range r.categories {
-			if cat.ID == choice {
-				selectedURL = cat.URL
-				break
-			}
-		}
-
-		if selectedURL == "" {
-			fmt.Printf("%s>> Errore: Categoria non valida.%s\n", ColorRed, ColorReset)
+		selected, ok := r.findCategory(input)
+		if !ok {
+			r.reportError(&choiceError{input})
 			continue
 		}
 
-		fmt.Println("Caricamento notizie in corso...")
+		r.selectCategory(selected)
+	}
+}
+
+// selectCategory fetches and shows the feed of cat, making it the current
+// one.
+func (r *RssReader) selectCategory(cat FeedCategory) {
+	recordSelection(cat.URL)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		rss, err := r.fetchFeed(ctx, selectedURL)
-		cancel()
+	fmt.Fprintln(r.out.diag, "Caricamento notizie in corso...")
 
-		if err != nil {
-			fmt.Printf("%s>> Errore nel scaricare il feed: %v%s\n", ColorRed, err, ColorReset)
-			continue
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	start := time.Now()
+	rss, err := r.fetchFeed(ctx, cat.URL)
+	elapsed := time.Since(start)
+	cancel()
+
+	if err != nil {
+		fmt.Fprintf(r.out.diag, "%s>> Errore nel scaricare il feed: %v%s\n", ColorRed, err, ColorReset)
+		return
+	}
 
-		r.displayFeed(rss)
+	r.current = rss
+	r.currentFeed = cat
+	r.tagFilter = ""
+	r.applyCategoryPrefs(cat)
+	r.displayFeed(rss)
+	shown, _ := r.countVisible(rss)
+	r.stats.feedsFetched++
+	r.stats.itemsShown += shown
+	if len(rss.Channel.Items) > 0 {
+		r.displayFooter(rss, elapsed)
 	}
 }
 
 func main() {
-	reader, err := NewRssReader()
+	os.Exit(runMain(os.Args[1:]))
+}
+
+// runMain runs adncli with the given arguments and returns the exit status.
+// Returning instead of calling os.Exit lets deferred cleanups, such as
+// closing the SSH tunnel, run.
+func runMain(argv []string) int {
+	cfg, args, err := loadConfig(argv)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%sErrore di configurazione: %v%s\n", ColorRed, err, ColorReset)
+		return 2
+	}
+
+	if cfg.ScreenReader {
+		disableColors()
+	}
+	dataHome = cfg.DataDir
+	dates, _ = lookupLocale(cfg.Locale)
+
+	var cmd *command
+	if len(args) > 0 {
+		var ok bool
+		if cmd, ok = findCommand(args[0]); !ok {
+			fmt.Fprintf(os.Stderr, "%sErrore: comando %q sconosciuto%s\n", ColorRed, args[0], ColorReset)
+			if name, ok := nearest(args[0], subcommandNames()); ok {
+				fmt.Fprintf(os.Stderr, "%s   Suggerimento: forse intendevi %q?%s\n", ColorYellow, name, ColorReset)
+			}
+			return 2
+		}
+	}
+
+	reader, err := NewRssReader(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%sErrore inizializzazione: %v%s\n", ColorRed, err, ColorReset)
-		os.Exit(1)
+		return 1
+	}
+	if cmd != nil || cfg.Briefing > 0 || cfg.Category != "" {
+		reader.out = newOutputRouter(false)
+	}
+
+	briefingCategories := reader.categories
+	if cfg.BriefingCategories != "" {
+		briefingCategories = nil
+		for _, key := range strings.Split(cfg.BriefingCategories, ",") {
+			cat, ok := reader.findCategory(key)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "%sErrore: categoria %q sconosciuta%s\n", ColorRed, key, ColorReset)
+				return 1
+			}
+			briefingCategories = append(briefingCategories, cat)
+		}
+	}
+
+	if cfg.SSHTunnel != "" {
+		fmt.Fprintf(reader.out.diag, "Apertura tunnel SSH verso %s...\n", cfg.SSHTunnel)
+		tunnel, err := startSSHTunnel(cfg.SSHTunnel)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%sErrore tunnel SSH: %v%s\n", ColorRed, err, ColorReset)
+			return 1
+		}
+		defer tunnel.Close()
+		reader.useProxy(tunnel.proxyURL())
+	}
+
+	if cmd != nil {
+		return exitCode(cmd.run(reader, args[1:]))
+	}
+
+	// The probe is opt-in, as offline it would only delay the menu. It
+	// dials directly, so it is meaningless behind a proxy, and an
+	// attached reader does not contact the feed servers at all.
+	proxied := cfg.Proxy != "" || cfg.SSHTunnel != "" ||
+		os.Getenv("HTTPS_PROXY") != "" || os.Getenv("https_proxy") != ""
+	if cfg.Probe && !proxied && cfg.Attach == "" {
+		for _, err := range reader.Probe() {
+			fmt.Fprintf(os.Stderr, "%s>> Attenzione: %v%s\n", ColorYellow, err, ColorReset)
+		}
+	}
+
+	if cfg.Briefing > 0 {
+		reader.Briefing(cfg.Briefing, briefingCategories)
+		return 0
+	}
+
+	if cfg.Category != "" {
+		return exitCode(reader.printFeeds(strings.Split(cfg.Category, ","), cfg.Limit, false))
+	}
+
+	if cfg.Warmup && cfg.Attach == "" {
+		reader.Warmup(context.Background())
 	}
 
 	reader.Run()
+	return 0
 }
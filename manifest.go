@@ -0,0 +1,213 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifest is a batch of operations run by "adncli run".
+//
+//	tasks:
+//	  - categories: [politica, esteri]
+//	    tag: governo
+//	    limit: 10
+//	    output: obsidian
+//	    file: ~/notes/politica.md
+//	    append: true
+type manifest struct {
+	Tasks []manifestTask `yaml:"tasks"`
+}
+
+// manifestTask fetches some categories and writes them in one format.
+type manifestTask struct {
+	Categories []string `yaml:"categories"`
+	Tag        string   `yaml:"tag"`
+	Limit      int      `yaml:"limit"`
+	Output     string   `yaml:"output"`
+	File       string   `yaml:"file"`
+	Append     bool     `yaml:"append"`
+	NoDesc     bool     `yaml:"no_desc"`
+
+	// categoryPrefs applies the per-category display preferences of the
+	// config file instead of Output and NoDesc, for adncli fetch.
+	categoryPrefs bool
+
+	// seen, if set, records the items of each category; with newOnly,
+	// only the items it did not have before are shown.
+	seen    *seenItems
+	newOnly bool
+}
+
+// loadManifest reads and validates a manifest file.
+func (r *RssReader) loadManifest(path string) (*manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i, task := range m.Tasks {
+		if len(task.Categories) == 0 {
+			return nil, fmt.Errorf("%s: task %d: nessuna categoria", path, i+1)
+		}
+		for _, key := range task.Categories {
+			if _, ok := r.findCategory(key); !ok {
+				return nil, fmt.Errorf("%s: task %d: categoria %q sconosciuta", path, i+1, key)
+			}
+		}
+		switch task.Output {
+		case "", "text", "table", "org", "obsidian":
+		default:
+			return nil, fmt.Errorf("%s: task %d: formato %q sconosciuto", path, i+1, task.Output)
+		}
+	}
+	return &m, nil
+}
+
+// RunManifest executes every task of the manifest at path, in order. A
+// failing task is reported and the others still run.
+func (r *RssReader) RunManifest(path string) error {
+	m, err := r.loadManifest(path)
+	if err != nil {
+		return err
+	}
+
+	// Batch output ends up in files and cron mails: no escape codes.
+	disableColors()
+
+	failed := 0
+	for i, task := range m.Tasks {
+		if err := r.runTask(task); err != nil {
+			fmt.Fprintf(r.out.diag, "task %d: %v\n", i+1, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d task su %d non riusciti", failed, len(m.Tasks))
+	}
+	return nil
+}
+
+// runManifestCommand implements "adncli run <manifest.yaml>".
+func (r *RssReader) runManifestCommand(args []string) error {
+	if len(args) != 1 {
+		return &usageError{usage: "adncli run <manifest.yaml>"}
+	}
+	return r.RunManifest(args[0])
+}
+
+// runTask fetches, filters and renders one manifest task. A task with a
+// file renders into memory first, so that a failed fetch leaves what the
+// file held before untouched.
+func (r *RssReader) runTask(task manifestTask) error {
+	if task.File == "" {
+		return r.renderTask(task)
+	}
+	path, err := expandHome(task.File)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	content := r.out.content
+	r.out.content = &buf
+	err = r.renderTask(task)
+	r.out.content = content
+	if err != nil {
+		return err
+	}
+	return writeTaskFile(path, buf.Bytes(), task.Append)
+}
+
+// writeTaskFile appends data to the file at path, or replaces the file
+// atomically, keeping its mode. Anything but a regular file, such as a
+// named pipe, is written in place.
+func writeTaskFile(path string, data []byte, appendTo bool) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	perm := os.FileMode(0o644)
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		perm = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	if !appendTo && (info == nil || info.Mode().IsRegular()) {
+		return writeFileAtomic(path, data, perm)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendTo {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// renderTask fetches, filters and renders the categories of task to the
+// content output.
+func (r *RssReader) renderTask(task manifestTask) error {
+	out, output, tagFilter, hideDesc := r.out, r.output, r.tagFilter, r.hideDesc
+	defer func() {
+		r.out, r.output, r.tagFilter, r.hideDesc = out, output, tagFilter, hideDesc
+	}()
+	r.output = task.Output
+	r.tagFilter = tagSlug(task.Tag)
+	r.hideDesc = task.NoDesc
+
+	for _, key := range task.Categories {
+		cat, _ := r.findCategory(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		rss, err := r.fetchFeed(ctx, cat.URL)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s: %w", cat.Name, err)
+		}
+
+		if task.seen != nil {
+			all := rss.Channel.Items
+			if task.newOnly {
+				rss.Channel.Items = task.seen.unseen(cat.URL, all)
+			}
+			task.seen.record(cat.URL, all)
+		}
+		if task.Limit > 0 && len(rss.Channel.Items) > task.Limit {
+			rss.Channel.Items = rss.Channel.Items[:task.Limit]
+		}
+		if task.newOnly && len(rss.Channel.Items) == 0 {
+			fmt.Fprintf(r.out.content, "Nessuna notizia nuova in %s dall'ultimo controllo.\n", cat.Name)
+			continue
+		}
+		r.currentFeed = cat
+		if task.categoryPrefs {
+			r.applyCategoryPrefs(cat)
+		}
+		r.displayFeed(rss)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// validFeedURL reports whether s is an absolute http or https URL.
+func validFeedURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// addMirrors registers fallback URLs for the feed at primary, after the
+// ones it already has.
+func (r *RssReader) addMirrors(name, primary string, mirrors []string) error {
+	for _, m := range mirrors {
+		if !validFeedURL(m) {
+			return fmt.Errorf("feed %q: URL mirror %q non valido", name, m)
+		}
+	}
+	if len(mirrors) == 0 {
+		return nil
+	}
+	if r.mirrors == nil {
+		r.mirrors = make(map[string][]string)
+	}
+	r.mirrors[primary] = append(r.mirrors[primary], mirrors...)
+	return nil
+}
+
+// fetchMirrored fetches the feed at primary, falling back to its mirrors
+// in order, and returns the URL that served it. A mirror serving the feed
+// is reported, so that a broken primary does not go unnoticed.
+func (r *RssReader) fetchMirrored(ctx context.Context, primary string) (*Rss, string, error) {
+	rss, err := r.fetchSource(ctx, primary)
+	if err == nil {
+		return rss, primary, nil
+	}
+
+	name := primary
+	if cat, ok := r.categoryByURL(primary); ok {
+		name = cat.Name
+	}
+	for _, mirror := range r.mirrors[primary] {
+		if ctx.Err() != nil {
+			break
+		}
+		rss, merr := r.fetchSource(ctx, mirror)
+		if merr == nil {
+			fmt.Fprintf(r.out.diag, "%s>> %s: sorgente principale non disponibile (%v), notizie dal mirror %s%s\n",
+				ColorYellow, name, err, mirror, ColorReset)
+			return rss, mirror, nil
+		}
+		err = fmt.Errorf("%w; mirror %s: %v", err, mirror, merr)
+	}
+	return nil, "", err
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// displayOrg renders the feed as org-mode entries, one heading per item
+// with its metadata in a property drawer.
+func (r *RssReader) displayOrg(rss *Rss) {
+	w := r.out.content
+	fmt.Fprintf(w, "* %s\n", strings.TrimSpace(rss.Channel.Title))
+
+	for _, item := range rss.Channel.Items {
+		if !r.visible(item) {
+			continue
+		}
+
+		fmt.Fprintf(w, "** %s\n", strings.TrimSpace(item.Title))
+		fmt.Fprintln(w, ":PROPERTIES:")
+		if t, ok := parsePubDate(item.PubDate); ok {
+			// Org reads the numeric part and ignores the day name.
+			t = t.Local()
+			fmt.Fprintf(w, ":PUBLISHED: [%s %s %s]\n", t.Format("2006-01-02"), abbrev(dates.weekdays[t.Weekday()]), t.Format("15:04"))
+		}
+		if source := r.sourceOf(item).Name; source != "" {
+			fmt.Fprintf(w, ":SOURCE:   %s\n", source)
+		}
+		fmt.Fprintf(w, ":URL:      %s\n", item.Link)
+		fmt.Fprintln(w, ":END:")
+
+		if desc := r.cleanText(r.sourceOf(item), item.Description); desc != "" && !r.hideDesc {
+			fmt.Fprintln(w, desc)
+		}
+	}
+
+	if footer := r.attribution(rss); footer != "" {
+		fmt.Fprintf(w, "** Attribuzione\n%s\n", footer)
+	}
+}
+
+// displayObsidian renders the feed as an Obsidian note: YAML frontmatter
+// describing the source, then one section per item.
+func (r *RssReader) displayObsidian(rss *Rss) {
+	w := r.out.content
+	source := r.currentFeed.Name
+	if source == "" {
+		source = strings.TrimSpace(rss.Channel.Title)
+	}
+
+	fmt.Fprintln(w, "---")
+	fmt.Fprintf(w, "title: %q\n", strings.TrimSpace(rss.Channel.Title))
+	fmt.Fprintf(w, "date: %s\n", time.Now().Format("2006-01-02T15:04"))
+	fmt.Fprintf(w, "source: %q\n", source)
+	fmt.Fprintf(w, "link: %s\n", rss.Channel.Link)
+	fmt.Fprintln(w, "tags: [adncli, news]")
+	fmt.Fprintln(w, "---")
+
+	for _, item := range rss.Channel.Items {
+		if !r.visible(item) {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n## [%s](%s)\n", strings.TrimSpace(item.Title), item.Link)
+		// Dataview only recognises ISO dates, so this one is not localized.
+		if t, ok := parsePubDate(item.PubDate); ok {
+			fmt.Fprintf(w, "- Pubblicato:: %s\n", t.Local().Format("2006-01-02 15:04"))
+		}
+		if item.Source != "" {
+			fmt.Fprintf(w, "- Categoria:: %s\n", item.Source)
+		}
+		if len(item.Categories) > 0 {
+			tags := make([]string, len(item.Categories))
+			for i, tag := range item.Categories {
+				tags[i] = "#" + tagSlug(tag)
+			}
+			fmt.Fprintf(w, "- Tag:: %s\n", strings.Join(tags, " "))
+		}
+		if desc := r.cleanText(r.sourceOf(item), item.Description); desc != "" && !r.hideDesc {
+			fmt.Fprintf(w, "\n%s\n", desc)
+		}
+	}
+
+	if footer := r.attribution(rss); footer != "" {
+		fmt.Fprintf(w, "\n---\n\n%s\n", footer)
+	}
+}
+
+// defaultAttribution is the footer of org and Markdown output, for
+// digests republished with their source.
+const defaultAttribution = "Fonte: {title} ({link}). {copyright}"
+
+// attribution renders the attribution template for rss: {title}, {link}
+// and {copyright} are the channel's, and a placeholder left empty takes
+// its parentheses with it. An empty template means no footer.
+func (r *RssReader) attribution(rss *Rss) string {
+	if r.footer == "" {
+		return ""
+	}
+	ch := rss.Channel
+	text := strings.NewReplacer(
+		"{title}", strings.TrimSpace(ch.Title),
+		"{link}", strings.TrimSpace(ch.Link),
+		"{copyright}", strings.TrimSpace(ch.Copyright),
+	).Replace(r.footer)
+	text = strings.ReplaceAll(text, " ()", "")
+	return strings.Join(strings.Fields(text), " ")
+}
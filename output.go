@@ -0,0 +1,27 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// outputRouter separates feed content from diagnostics (errors, progress,
+// menus and prompts), so that redirecting stdout captures clean data.
+type outputRouter struct {
+	content io.Writer
+	diag    io.Writer
+}
+
+// newOutputRouter returns the router for the given mode. Interactive
+// sessions keep everything on stdout, where the user is reading; scripted
+// modes send diagnostics to stderr.
+func newOutputRouter(interactive bool) outputRouter {
+	if interactive {
+		return outputRouter{content: os.Stdout, diag: os.Stdout}
+	}
+	return outputRouter{content: os.Stdout, diag: os.Stderr}
+}
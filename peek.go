@@ -0,0 +1,104 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// peekLimit caps how much of a page is read when looking for metadata.
+const peekLimit = 256 << 10
+
+var (
+	titleRegex   = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	metaTagRegex = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attrRegex    = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// pagePreview is the lightweight metadata gathered by peek.
+type pagePreview struct {
+	Status      string
+	FinalURL    string
+	ContentType string
+	Title       string
+	Description string
+}
+
+// peekPage fetches the start of link and extracts its preview metadata.
+func (r *RssReader) peekPage(ctx context.Context, link string) (*pagePreview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.fetcher.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	p := &pagePreview{
+		Status:      resp.Status,
+		FinalURL:    resp.Request.URL.String(),
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if !strings.Contains(p.ContentType, "html") {
+		return p, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, peekLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	if m := titleRegex.FindSubmatch(body); m != nil {
+		p.Title = strings.TrimSpace(html.UnescapeString(string(m[1])))
+	}
+
+	for _, tag := range metaTagRegex.FindAll(body, -1) {
+		attrs := make(map[string]string)
+		for _, a := range attrRegex.FindAllSubmatch(tag, -1) {
+			attrs[strings.ToLower(string(a[1]))] = html.UnescapeString(strings.Trim(string(a[2]), `"'`))
+		}
+		if attrs["property"] == "og:description" || (p.Description == "" && attrs["name"] == "description") {
+			p.Description = strings.TrimSpace(attrs["content"])
+		}
+	}
+
+	return p, nil
+}
+
+// displayPeek prints the preview of the given item's link.
+func (r *RssReader) displayPeek(item *Item) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	p, err := r.peekPage(ctx, item.Link)
+	if err != nil {
+		fmt.Fprintf(r.out.diag, "%s>> Errore nell'anteprima: %v%s\n", ColorRed, err, ColorReset)
+		return
+	}
+
+	fields := [][2]string{
+		{"Stato", p.Status},
+		{"URL finale", p.FinalURL},
+		{"Tipo", p.ContentType},
+		{"Titolo pagina", p.Title},
+		{"Descrizione", p.Description},
+	}
+	fmt.Fprintln(r.out.content)
+	for _, f := range fields {
+		value := f[1]
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(r.out.content, "%s%s:%s %s\n", ColorCyan, f[0], ColorReset, value)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// probeTimeout bounds each step of the pre-flight connectivity check.
+const probeTimeout = 3 * time.Second
+
+// probeHost checks that host resolves and accepts a TLS handshake on port
+// 443. On failure it returns a diagnostic meant for the user.
+func probeHost(ctx context.Context, host string) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("impossibile risolvere %s: verifica la connessione di rete o il server DNS", host)
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: probeTimeout},
+		Config:    &tls.Config{ServerName: host},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, "443"))
+	if err == nil {
+		conn.Close()
+		return nil
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr):
+		return fmt.Errorf("certificato TLS inatteso per %s: probabile captive portal (Wi-Fi pubblico) o proxy che intercetta il traffico", host)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("%s non risponde: la rete potrebbe richiedere un proxy o bloccare la porta 443", host)
+	default:
+		return fmt.Errorf("connessione a %s fallita: %v", host, err)
+	}
+}
+
+// Probe runs the pre-flight check against every distinct feed host, all
+// at once, so that it takes at most probeTimeout per step however many
+// hosts there are. The errors come in the order of the categories.
+func (r *RssReader) Probe() []error {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, cat := range r.categories {
+		u, err := url.Parse(cat.URL)
+		if err != nil || u.Scheme != "https" || seen[u.Hostname()] {
+			continue
+		}
+		seen[u.Hostname()] = true
+		hosts = append(hosts, u.Hostname())
+	}
+
+	results := make([]error, len(hosts))
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Go(func() {
+			results[i] = probeHost(context.Background(), host)
+		})
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Warmup sends a HEAD request to one feed per host through the reader's
+// fetcher, leaving resolved, TLS-established connections in its idle pool
+// for the first real fetch. It is meant to run in the background.
+func (r *RssReader) Warmup(ctx context.Context) {
+	seen := make(map[string]bool)
+	for _, cat := range r.categories {
+		u, err := url.Parse(cat.URL)
+		if err != nil || seen[u.Host] {
+			continue
+		}
+		seen[u.Host] = true
+
+		go func(feedURL string) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodHead, feedURL, nil)
+			if err != nil {
+				return
+			}
+			if resp, err := r.fetcher.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}(cat.URL)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queuedItem is an item set aside to be read in a later session.
+type queuedItem struct {
+	Title    string    `json:"title"`
+	Link     string    `json:"link"`
+	Category string    `json:"category,omitempty"`
+	Added    time.Time `json:"added"`
+}
+
+// readQueue is the persistent "read later" list. Unlike bookmarks, items
+// leave the queue as soon as they are opened.
+type readQueue struct {
+	path  string
+	Items []queuedItem `json:"items"`
+}
+
+// loadQueue reads the queue from the data directory.
+func loadQueue() (*readQueue, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &readQueue{path: filepath.Join(dir, "queue.json")}
+	if err := loadJSON(q.path, q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// save writes the queue back to disk.
+func (q *readQueue) save() error {
+	return saveJSON(q.path, q)
+}
+
+// add appends item unless its link is already queued.
+func (q *readQueue) add(item queuedItem) bool {
+	for _, it := range q.Items {
+		if it.Link == item.Link {
+			return false
+		}
+	}
+	q.Items = append(q.Items, item)
+	return true
+}
+
+// remove drops the item with the given link, reporting whether it was queued.
+func (q *readQueue) remove(link string) bool {
+	for i, it := range q.Items {
+		if it.Link == link {
+			q.Items = append(q.Items[:i], q.Items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// print lists the queue with 1-based numbers.
+func (q *readQueue) print(w io.Writer) {
+	if len(q.Items) == 0 {
+		fmt.Fprintln(w, "La coda di lettura è vuota.")
+		return
+	}
+	for i, it := range q.Items {
+		fmt.Fprintf(w, "%s[%d]%s %s%s%s\n", ColorBlue, i+1, ColorReset, ColorBold, it.Title, ColorReset)
+		meta := []string{dates.short(it.Added)}
+		if it.Category != "" {
+			meta = append([]string{it.Category}, meta...)
+		}
+		fmt.Fprintf(w, "    %s%s%s\n    %s\n", ColorCyan, strings.Join(meta, " · "), ColorReset, it.Link)
+	}
+}
+
+// queueItem adds item from the current feed to the read-later queue,
+// reporting false if it was already queued.
+func (r *RssReader) queueItem(item *Item) (bool, error) {
+	queued := queuedItem{
+		Title:    strings.TrimSpace(item.Title),
+		Link:     item.Link,
+		Category: r.sourceOf(*item).Name,
+		Added:    time.Now(),
+	}
+	if r.attached != nil {
+		return r.attachedEnqueue(queued)
+	}
+
+	q, err := loadQueue()
+	if err != nil {
+		return false, err
+	}
+	if !q.add(queued) {
+		return false, nil
+	}
+	return true, q.save()
+}
+
+// openItem opens the item in the browser, records it in the opened-items
+// log and takes it off the read-later queue if it was there.
+func (r *RssReader) openItem(title, link, category string) error {
+	if err := openURL(link); err != nil {
+		return err
+	}
+	r.stats.itemsOpened++
+	recordOpened(openedItem{
+		Title:    title,
+		Link:     link,
+		Category: category,
+		Opened:   time.Now(),
+	})
+
+	if r.attached != nil {
+		return r.attachedDequeue(link)
+	}
+	q, err := loadQueue()
+	if err != nil {
+		return err
+	}
+	if q.remove(link) {
+		return q.save()
+	}
+	return nil
+}
+
+// runQueue implements "adncli queue [open N | clear]".
+func (r *RssReader) runQueue(args []string) error {
+	load := loadQueue
+	if r.attached != nil {
+		load = r.attachedQueue
+	}
+	q, err := load()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		q.print(r.out.content)
+		return nil
+	}
+
+	switch args[0] {
+	case "open":
+		if len(args) < 2 {
+			return &usageError{usage: "adncli queue open <numero>"}
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > len(q.Items) {
+			return fmt.Errorf("elemento %q non valido", args[1])
+		}
+		it := q.Items[n-1]
+		return r.openItem(it.Title, it.Link, it.Category)
+	case "clear":
+		if r.attached != nil {
+			for _, it := range q.Items {
+				if err := r.attachedDequeue(it.Link); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		q.Items = nil
+		return q.save()
+	default:
+		return fmt.Errorf("comando queue sconosciuto: %s", args[0])
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// errNoRaw is returned for items that were not decoded from XML in this
+// session, such as JSON Feed items and those of an attached server.
+var errNoRaw = errors.New("XML originale non disponibile per questa notizia")
+
+// rawRecorder keeps the bytes read through it from a mark onwards, so that
+// the source of each item can be retained while the feed is decoded.
+type rawRecorder struct {
+	r    io.Reader
+	buf  []byte
+	base int64 // stream offset of buf[0]
+}
+
+func (rec *rawRecorder) Read(p []byte) (int, error) {
+	n, err := rec.r.Read(p)
+	rec.buf = append(rec.buf, p[:n]...)
+	return n, err
+}
+
+// mark forgets the bytes before stream offset off.
+func (rec *rawRecorder) mark(off int64) {
+	if d := off - rec.base; d > 0 && d <= int64(len(rec.buf)) {
+		rec.buf = rec.buf[d:]
+		rec.base = off
+	}
+}
+
+// slice returns a copy of the bytes between stream offsets from and to,
+// which must follow the last mark.
+func (rec *rawRecorder) slice(from, to int64) []byte {
+	from, to = from-rec.base, to-rec.base
+	if from < 0 || to > int64(len(rec.buf)) || from > to {
+		return nil
+	}
+	return bytes.Clone(rec.buf[from:to])
+}
+
+// prettyXML re-indents an XML fragment, one element per line. Text that
+// would need escaping, such as HTML descriptions, is shown as CDATA so
+// that it reads as it does in the feed.
+func prettyXML(raw []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(raw))
+	dec.Strict = false
+
+	var (
+		b        strings.Builder
+		depth    int
+		open     bool // the last start tag still lacks its '>'
+		children []bool
+	)
+	name := func(n xml.Name) string {
+		if n.Space != "" {
+			return n.Space + ":" + n.Local
+		}
+		return n.Local
+	}
+	newline := func() {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strings.Repeat("  ", depth))
+	}
+	closeTag := func() {
+		if open {
+			b.WriteByte('>')
+			open = false
+		}
+	}
+
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			closeTag()
+			if len(children) > 0 {
+				children[len(children)-1] = true
+			}
+			newline()
+			b.WriteString("<" + name(t.Name))
+			for _, attr := range t.Attr {
+				b.WriteString(" " + name(attr.Name) + `="`)
+				xml.EscapeText(&b, []byte(attr.Value))
+				b.WriteByte('"')
+			}
+			open = true
+			children = append(children, false)
+			depth++
+		case xml.EndElement:
+			depth--
+			hadChildren := len(children) > 0 && children[len(children)-1]
+			if len(children) > 0 {
+				children = children[:len(children)-1]
+			}
+			switch {
+			case open:
+				b.WriteString("/>")
+				open = false
+			case hadChildren:
+				newline()
+				fallthrough
+			default:
+				b.WriteString("</" + name(t.Name) + ">")
+			}
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" {
+				continue
+			}
+			closeTag()
+			if strings.ContainsAny(text, "<&") {
+				b.WriteString("<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>")
+			} else {
+				b.WriteString(text)
+			}
+		case xml.Comment:
+			closeTag()
+			newline()
+			b.WriteString("<!--" + string(t) + "-->")
+		}
+	}
+	return b.String(), nil
+}
+
+// page shows text through $PAGER, or less, when the output is a terminal,
+// and prints it otherwise or when no pager can be started.
+func (r *RssReader) page(text string) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if r.out.content != io.Writer(os.Stdout) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		io.WriteString(r.out.content, text)
+		return
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less", "-FRX"}
+	}
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		io.WriteString(r.out.content, text)
+	}
+}
+
+// displayRaw shows the XML the item was decoded from, re-indented.
+func (r *RssReader) displayRaw(item *Item) error {
+	if len(item.Raw) == 0 {
+		return errNoRaw
+	}
+	text, err := prettyXML(item.Raw)
+	if err != nil {
+		// Show what was received even if it does not re-parse alone.
+		text = string(item.Raw)
+	}
+	r.page(text)
+	return nil
+}
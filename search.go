@@ -0,0 +1,93 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// searchResult holds the items of a category matching a search.
+type searchResult struct {
+	category FeedCategory
+	items    []Item
+}
+
+// search fetches each category and keeps the items whose title or cleaned
+// description contains term, ignoring case. Categories that fail to load
+// are returned as errors and do not stop the search.
+func (r *RssReader) search(term string, categories []FeedCategory) ([]searchResult, []error) {
+	term = strings.ToLower(strings.TrimSpace(term))
+
+	var (
+		results []searchResult
+		errs    []error
+	)
+	for _, cat := range categories {
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		rss, err := r.fetchFeed(ctx, cat.URL)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cat.Name, err))
+			continue
+		}
+
+		res := searchResult{category: cat}
+		for _, item := range rss.Channel.Items {
+			text := strings.ToLower(item.Title + " " + r.cleanText(cat, item.Description))
+			if strings.Contains(text, term) {
+				res.items = append(res.items, item)
+			}
+		}
+		if len(res.items) > 0 {
+			results = append(results, res)
+		}
+	}
+	return results, errs
+}
+
+// runSearch implements "adncli search [--category C] <termine>".
+func (r *RssReader) runSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	only := fs.String("category", "", "categorie in cui cercare, separate da virgola (predefinito: tutte)")
+	words, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	term := strings.Join(words, " ")
+	if strings.TrimSpace(term) == "" {
+		return &usageError{usage: "adncli search [--category C] <termine>"}
+	}
+
+	categories := r.categories
+	if *only != "" {
+		categories = nil
+		for _, key := range strings.Split(*only, ",") {
+			cat, ok := r.findCategory(key)
+			if !ok {
+				return fmt.Errorf("categoria %q sconosciuta", strings.TrimSpace(key))
+			}
+			categories = append(categories, cat)
+		}
+	}
+
+	plainIfPiped()
+	results, errs := r.search(term, categories)
+	for _, err := range errs {
+		fmt.Fprintf(r.out.diag, "%s>> Errore: %v%s\n", ColorRed, err, ColorReset)
+	}
+	if len(results) == 0 {
+		fmt.Fprintf(r.out.content, "Nessuna notizia trovata per %q.\n", term)
+		return nil
+	}
+
+	for _, res := range results {
+		r.currentFeed = res.category
+		r.displayFeed(&Rss{Channel: Channel{Title: res.category.Name, Items: res.items}})
+	}
+	return nil
+}
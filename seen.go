@@ -0,0 +1,79 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// seenItems remembers, for each feed URL, the items found by the last
+// "adncli fetch", so that --new-only can show what appeared since.
+type seenItems struct {
+	path  string
+	Feeds map[string][]string `json:"feeds"`
+}
+
+// loadSeen reads the seen items from the data directory.
+func loadSeen() (*seenItems, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &seenItems{
+		path:  filepath.Join(dir, "seen.json"),
+		Feeds: make(map[string][]string),
+	}
+	if err := loadJSON(s.path, s); err != nil {
+		return nil, err
+	}
+	if s.Feeds == nil {
+		s.Feeds = make(map[string][]string)
+	}
+	return s, nil
+}
+
+// save writes the seen items back to disk.
+func (s *seenItems) save() error {
+	return saveJSON(s.path, s)
+}
+
+// itemKey identifies an item across fetches: its GUID, else its link,
+// else its title.
+func itemKey(item Item) string {
+	for _, key := range []string{item.GUID, item.Link, item.Title} {
+		if key = strings.TrimSpace(key); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// unseen returns the items of the feed at url that its last recorded
+// fetch did not have. Every item of a feed never recorded is new.
+func (s *seenItems) unseen(url string, items []Item) []Item {
+	known := make(map[string]bool)
+	for _, key := range s.Feeds[url] {
+		known[key] = true
+	}
+
+	var fresh []Item
+	for _, item := range items {
+		if !known[itemKey(item)] {
+			fresh = append(fresh, item)
+		}
+	}
+	return fresh
+}
+
+// record replaces what is remembered of the feed at url with items.
+func (s *seenItems) record(url string, items []Item) {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, itemKey(item))
+	}
+	s.Feeds[url] = keys
+}
@@ -0,0 +1,458 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiCategory is the JSON form of a category.
+type apiCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// apiItem is the JSON form of an item, with its description cleaned.
+type apiItem struct {
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	Description string    `json:"description,omitempty"`
+	Published   time.Time `json:"published,omitzero"`
+	Tags        []string  `json:"tags,omitempty"`
+}
+
+// apiFeed is the JSON form of the items of a category.
+type apiFeed struct {
+	Category apiCategory `json:"category"`
+	Title    string      `json:"title,omitempty"`
+	Items    []apiItem   `json:"items"`
+}
+
+// apiQueued is the body of a request adding an item to the queue.
+type apiQueued struct {
+	Title    string `json:"title"`
+	Link     string `json:"link"`
+	Category string `json:"category"`
+}
+
+func newAPICategory(cat FeedCategory) apiCategory {
+	return apiCategory{ID: cat.ID, Name: cat.Name, URL: cat.URL}
+}
+
+// newAPIFeed converts items of cat to their JSON form.
+func (r *RssReader) newAPIFeed(cat FeedCategory, title string, items []Item) apiFeed {
+	feed := apiFeed{Category: newAPICategory(cat), Title: strings.TrimSpace(title), Items: []apiItem{}}
+	for _, item := range items {
+		published, _ := parsePubDate(item.PubDate)
+		feed.Items = append(feed.Items, apiItem{
+			Title:       strings.TrimSpace(item.Title),
+			Link:        item.Link,
+			Description: r.cleanText(cat, item.Description),
+			Published:   published,
+			Tags:        item.Categories,
+		})
+	}
+	return feed
+}
+
+// apiVersions lists the API versions served, oldest first; each is
+// mounted under /v<N>/. Within a version fields may be added but are never
+// removed, renamed or given a different meaning: such changes need a new
+// version, and old versions keep being served.
+var apiVersions = []int{1}
+
+// apiVersion is the version this build renders. With more than one
+// version served, handlers will branch on the negotiated one.
+const apiVersion = 1
+
+// apiResponse is the envelope of every API response. SchemaVersion lets
+// scripts check what they parse; exactly one of Data and Error is set.
+type apiResponse struct {
+	SchemaVersion int    `json:"schema_version"`
+	Data          any    `json:"data,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// send encodes resp with the given status.
+func send(w http.ResponseWriter, status int, resp apiResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Adncli-Schema-Version", strconv.Itoa(resp.SchemaVersion))
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(resp)
+}
+
+// writeJSON sends v as the data of a response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	send(w, status, apiResponse{SchemaVersion: apiVersion, Data: v})
+}
+
+// writeError sends msg as the error of a response with the given status.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	send(w, status, apiResponse{SchemaVersion: apiVersion, Error: msg})
+}
+
+// acceptRegex matches the media type clients use to ask for a version on
+// unversioned paths, as in "Accept: application/vnd.adncli.v1+json".
+var acceptRegex = regexp.MustCompile(`application/vnd\.adncli\.v(\d+)\+json`)
+
+// negotiate serves unversioned paths with the version requested in the
+// Accept header, or the latest one. An unknown version is refused with
+// 406 rather than silently answered in another schema.
+func negotiate(versions map[int]http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		version := apiVersions[len(apiVersions)-1]
+		if m := acceptRegex.FindStringSubmatch(req.Header.Get("Accept")); m != nil {
+			version, _ = strconv.Atoi(m[1])
+		}
+		h, ok := versions[version]
+		if !ok {
+			writeError(w, http.StatusNotAcceptable, fmt.Sprintf("versione %d dell'API non disponibile (disponibili: %v)", version, apiVersions))
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// versionPathRegex matches an explicit version at the start of a path,
+// as in "/v2/categories".
+var versionPathRegex = regexp.MustCompile(`^/v(\d+)/`)
+
+// discardWriter records the status a handler writes, so that the plain
+// text errors of http.ServeMux can be sent again as JSON.
+type discardWriter struct {
+	header http.Header
+	status int
+}
+
+func (d *discardWriter) Header() http.Header         { return d.header }
+func (d *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardWriter) WriteHeader(status int)      { d.status = status }
+
+// jsonErrors serves requests that match no route of mux, which
+// http.ServeMux answers in plain text, with the 404 or 405 of the API
+// envelope instead.
+func jsonErrors(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		h, pattern := mux.Handler(req)
+		if pattern != "" {
+			mux.ServeHTTP(w, req)
+			return
+		}
+		d := &discardWriter{header: make(http.Header), status: http.StatusOK}
+		h.ServeHTTP(d, req)
+		if allow := d.header.Get("Allow"); allow != "" {
+			w.Header().Set("Allow", allow)
+		}
+		msg := fmt.Sprintf("percorso %s sconosciuto", req.URL.Path)
+		switch {
+		case d.status == http.StatusMethodNotAllowed:
+			msg = fmt.Sprintf("metodo %s non consentito per %s", req.Method, req.URL.Path)
+		case versionPathRegex.MatchString(req.URL.Path):
+			version := versionPathRegex.FindStringSubmatch(req.URL.Path)[1]
+			msg = fmt.Sprintf("versione %s dell'API non disponibile (disponibili: %v)", version, apiVersions)
+		}
+		writeError(w, d.status, msg)
+	})
+}
+
+// apiServer serves the JSON API of adncli serve.
+type apiServer struct {
+	r *RssReader
+
+	// queueMu serialises the read-modify-write cycles on the queue file.
+	queueMu sync.Mutex
+}
+
+// routes returns the handler of every API endpoint: /v1/... for
+// explicit versions, /versions to discover them and unversioned paths
+// negotiated through the Accept header. Unknown paths and versions get
+// a JSON error like every other response.
+func (s *apiServer) routes() http.Handler {
+	v1 := http.NewServeMux()
+	v1.HandleFunc("GET /categories", s.categories)
+	v1.HandleFunc("GET /feeds/{category}", s.feed)
+	v1.HandleFunc("GET /search", s.search)
+	v1.HandleFunc("GET /queue", s.queue)
+	v1.HandleFunc("POST /queue", s.enqueue)
+	v1.HandleFunc("DELETE /queue", s.dequeue)
+
+	api := jsonErrors(v1)
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", http.StripPrefix("/v1", api))
+	mux.HandleFunc("GET /versions", s.versions)
+	mux.Handle("/", negotiate(map[int]http.Handler{1: api}))
+	return mux
+}
+
+func (s *apiServer) versions(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"versions": apiVersions,
+		"latest":   apiVersions[len(apiVersions)-1],
+	})
+}
+
+func (s *apiServer) categories(w http.ResponseWriter, req *http.Request) {
+	cats := make([]apiCategory, len(s.r.categories))
+	for i, cat := range s.r.categories {
+		cats[i] = newAPICategory(cat)
+	}
+	writeJSON(w, http.StatusOK, cats)
+}
+
+func (s *apiServer) feed(w http.ResponseWriter, req *http.Request) {
+	cat, ok := s.r.findCategory(req.PathValue("category"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("categoria %q sconosciuta", req.PathValue("category")))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), s.r.timeout)
+	defer cancel()
+	rss, err := s.r.fetchFeed(ctx, cat.URL)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.r.newAPIFeed(cat, rss.Channel.Title, rss.Channel.Items))
+}
+
+func (s *apiServer) search(w http.ResponseWriter, req *http.Request) {
+	term := req.URL.Query().Get("q")
+	if strings.TrimSpace(term) == "" {
+		writeError(w, http.StatusBadRequest, "parametro q mancante")
+		return
+	}
+
+	results, errs := s.r.search(term, s.r.categories)
+	if len(results) == 0 && len(errs) > 0 {
+		writeError(w, http.StatusBadGateway, errors.Join(errs...).Error())
+		return
+	}
+	feeds := []apiFeed{}
+	for _, res := range results {
+		feeds = append(feeds, s.r.newAPIFeed(res.category, "", res.items))
+	}
+	writeJSON(w, http.StatusOK, feeds)
+}
+
+func (s *apiServer) queue(w http.ResponseWriter, req *http.Request) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	q, err := loadQueue()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	items := q.Items
+	if items == nil {
+		items = []queuedItem{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *apiServer) enqueue(w http.ResponseWriter, req *http.Request) {
+	var body apiQueued
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 64<<10)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "JSON non valido: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(body.Link) == "" {
+		writeError(w, http.StatusBadRequest, "campo link mancante")
+		return
+	}
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	q, err := loadQueue()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	item := queuedItem{
+		Title:    strings.TrimSpace(body.Title),
+		Link:     strings.TrimSpace(body.Link),
+		Category: body.Category,
+		Added:    time.Now(),
+	}
+	if !q.add(item) {
+		writeJSON(w, http.StatusOK, item)
+		return
+	}
+	if err := q.save(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, item)
+}
+
+func (s *apiServer) dequeue(w http.ResponseWriter, req *http.Request) {
+	link := strings.TrimSpace(req.URL.Query().Get("link"))
+	if link == "" {
+		writeError(w, http.StatusBadRequest, "parametro link mancante")
+		return
+	}
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	q, err := loadQueue()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	removed := q.remove(link)
+	if removed {
+		if err := q.save(); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"removed": removed})
+}
+
+// serveAuth holds the credentials accepted by adncli serve. With none set
+// the API is open.
+type serveAuth struct {
+	token          string
+	user, password string
+}
+
+func (a serveAuth) enabled() bool {
+	return a.token != "" || a.user != ""
+}
+
+// allows reports whether req carries a valid bearer token or basic-auth
+// pair, comparing in constant time.
+func (a serveAuth) allows(req *http.Request) bool {
+	equal := func(x, y string) bool {
+		return subtle.ConstantTimeCompare([]byte(x), []byte(y)) == 1
+	}
+	if a.token != "" {
+		if got, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok && equal(got, a.token) {
+			return true
+		}
+	}
+	if a.user != "" {
+		if user, password, ok := req.BasicAuth(); ok && equal(user, a.user) && equal(password, a.password) {
+			return true
+		}
+	}
+	return false
+}
+
+// protect wraps next with authentication and, when readOnly is set,
+// rejects every request that could change state.
+func protect(next http.Handler, auth serveAuth, readOnly bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if auth.enabled() && !auth.allows(req) {
+			if auth.user != "" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="adncli", charset="UTF-8"`)
+			}
+			writeError(w, http.StatusUnauthorized, "autenticazione richiesta")
+			return
+		}
+		if readOnly && req.Method != http.MethodGet && req.Method != http.MethodHead {
+			writeError(w, http.StatusForbidden, "server in sola lettura")
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// isLoopback reports whether addr only accepts local connections.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// logRequests reports every request on w once it has been served.
+func (r *RssReader) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, req)
+		fmt.Fprintf(r.out.diag, "%s %s %s\n", req.Method, req.URL.RequestURI(), time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// serveEnvPrefix starts the environment variables setting serve flags,
+// so that secrets need not appear on the command line: --token is read
+// from ADNCLI_SERVE_TOKEN.
+const serveEnvPrefix = envPrefix + "SERVE_"
+
+// runServe implements "adncli serve". It stops cleanly on Ctrl-C.
+func (r *RssReader) runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "indirizzo su cui ascoltare")
+	var auth serveAuth
+	fs.StringVar(&auth.token, "token", "", "token richiesto come \"Authorization: Bearer <token>\" (o "+serveEnvPrefix+"TOKEN)")
+	fs.StringVar(&auth.user, "user", "", "utente per l'autenticazione HTTP Basic (o "+serveEnvPrefix+"USER)")
+	fs.StringVar(&auth.password, "password", "", "password per l'autenticazione HTTP Basic (o "+serveEnvPrefix+"PASSWORD)")
+	readOnly := fs.Bool("read-only", false, "rifiuta le richieste che modificano i dati, come l'aggiunta alla coda")
+	if err := applyEnv(fs, serveEnvPrefix, os.LookupEnv, nil); err != nil {
+		return err
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 || (auth.user == "") != (auth.password == "") {
+		return &usageError{usage: "adncli serve [--addr host:porta] [--token T | --user U --password P] [--read-only]"}
+	}
+
+	// Logs go to a terminal or a journal, never to a client.
+	disableColors()
+
+	s := &apiServer{r: r}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           r.logRequests(protect(s.routes(), auth, *readOnly)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	go r.pruneArchiveEvery(ctx, archivePruneInterval)
+
+	if !auth.enabled() && !isLoopback(*addr) {
+		fmt.Fprintf(r.out.diag, ">> Attenzione: %s è raggiungibile da altri computer senza autenticazione; usa --token o --user e --password\n", *addr)
+	}
+	fmt.Fprintf(r.out.diag, "In ascolto su http://%s\n", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
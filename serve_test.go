@@ -0,0 +1,47 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoutesErrors(t *testing.T) {
+	s := &apiServer{r: &RssReader{}}
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{"GET", "/categories", http.StatusOK},
+		{"GET", "/v1/categories", http.StatusOK},
+		{"GET", "/nowhere", http.StatusNotFound},
+		{"GET", "/v1/nowhere", http.StatusNotFound},
+		{"GET", "/v2/categories", http.StatusNotFound},
+		{"PUT", "/v1/queue", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.routes().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			var resp apiResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("body is not JSON: %v\n%s", err, rec.Body)
+			}
+			if resp.SchemaVersion != apiVersion {
+				t.Errorf("schema_version %d, want %d", resp.SchemaVersion, apiVersion)
+			}
+			if (tt.status != http.StatusOK) != (resp.Error != "") {
+				t.Errorf("error %q for status %d", resp.Error, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// starredItem is an item bookmarked from a feed.
+type starredItem struct {
+	Title    string    `json:"title"`
+	Link     string    `json:"link"`
+	Category string    `json:"category,omitempty"`
+	Saved    time.Time `json:"saved"`
+}
+
+// bookmarks is the persistent list of starred items. Unlike the read
+// queue, items stay until they are removed.
+type bookmarks struct {
+	path  string
+	Items []starredItem `json:"items"`
+}
+
+// loadBookmarks reads the bookmarks from the data directory.
+func loadBookmarks() (*bookmarks, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bookmarks{path: filepath.Join(dir, "starred.json")}
+	if err := loadJSON(b.path, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// save writes the bookmarks back to disk.
+func (b *bookmarks) save() error {
+	return saveJSON(b.path, b)
+}
+
+// add appends item unless its link is already starred.
+func (b *bookmarks) add(item starredItem) bool {
+	for _, it := range b.Items {
+		if it.Link == item.Link {
+			return false
+		}
+	}
+	b.Items = append(b.Items, item)
+	return true
+}
+
+// print lists the bookmarks with 1-based numbers, most recent last.
+func (b *bookmarks) print(w io.Writer) {
+	if len(b.Items) == 0 {
+		fmt.Fprintln(w, "Non ci sono notizie salvate.")
+		return
+	}
+	for i, it := range b.Items {
+		fmt.Fprintf(w, "%s[%d]%s %s%s%s\n", ColorBlue, i+1, ColorReset, ColorBold, it.Title, ColorReset)
+		meta := []string{"salvata " + dates.short(it.Saved)}
+		if it.Category != "" {
+			meta = append([]string{it.Category}, meta...)
+		}
+		fmt.Fprintf(w, "    %s%s%s\n    %s\n", ColorCyan, strings.Join(meta, " · "), ColorReset, it.Link)
+	}
+}
+
+// starItem bookmarks item from the current feed, reporting false if it
+// was already starred.
+func (r *RssReader) starItem(item *Item) (bool, error) {
+	b, err := loadBookmarks()
+	if err != nil {
+		return false, err
+	}
+	added := b.add(starredItem{
+		Title:    strings.TrimSpace(item.Title),
+		Link:     item.Link,
+		Category: r.sourceOf(*item).Name,
+		Saved:    time.Now(),
+	})
+	if !added {
+		return false, nil
+	}
+	return true, b.save()
+}
+
+// runStarred implements "adncli starred [open N | remove N]".
+func (r *RssReader) runStarred(args []string) error {
+	b, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		plainIfPiped()
+		b.print(r.out.content)
+		return nil
+	}
+
+	usage := &usageError{usage: "adncli starred [open N | remove N]"}
+	if len(args) != 2 || (args[0] != "open" && args[0] != "remove") {
+		return usage
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 || n > len(b.Items) {
+		return fmt.Errorf("elemento %q non valido", args[1])
+	}
+	it := b.Items[n-1]
+
+	if args[0] == "open" {
+		return r.openItem(it.Title, it.Link, it.Category)
+	}
+	b.Items = append(b.Items[:n-1], b.Items[n:]...)
+	if err := b.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.out.diag, "Rimossa dalle notizie salvate: %s\n", it.Title)
+	return nil
+}
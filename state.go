@@ -0,0 +1,177 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// dataHome, when set from the configuration, replaces the XDG data
+// directory.
+var dataHome string
+
+// dataDir returns the directory adncli keeps persistent data in, dataHome
+// or $XDG_DATA_HOME/adncli or ~/.local/share/adncli, creating it if needed.
+func dataDir() (string, error) {
+	if dataHome != "" {
+		if err := os.MkdirAll(dataHome, 0o755); err != nil {
+			return "", err
+		}
+		return dataHome, nil
+	}
+
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot locate home directory: %w", err)
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+
+	dir := filepath.Join(base, "adncli")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// writeFileAtomic replaces path with data without ever leaving a partially
+// written file behind: it writes a temporary file in the same directory,
+// syncs it to disk and renames it over path.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	// Removing after a successful rename is a harmless no-op.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself.
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// stateVersion is the schema version of the files saved by saveJSON. Bump
+// it when a change to a persisted type cannot be read by older code, and
+// teach loadJSON to migrate the previous version.
+const stateVersion = 1
+
+// stateFile is the envelope saveJSON wraps data in, so that loadJSON can
+// detect files truncated or edited by hand and files from newer releases.
+type stateFile struct {
+	Version  int             `json:"version"`
+	Checksum string          `json:"checksum"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// checksum returns the SHA-256 of the compacted JSON data.
+func checksum(data []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(buf.Bytes())), nil
+}
+
+// loadJSON decodes the file at path into v. A missing file leaves v
+// untouched and is not an error. Files written before versioning are read
+// as they are; corrupt files are moved aside with a warning and v is left
+// untouched, so adncli starts afresh instead of failing. A file from a
+// newer schema is an error, to avoid overwriting it.
+func loadJSON(path string, v any) error {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var file stateFile
+	if err := json.Unmarshal(raw, &file); err != nil || file.Version == 0 {
+		// Unversioned files hold the data itself.
+		if err := json.Unmarshal(raw, v); err != nil {
+			return quarantine(path, err)
+		}
+		return nil
+	}
+
+	if file.Version > stateVersion {
+		return fmt.Errorf("%s: schema version %d is newer than this adncli supports (%d)", path, file.Version, stateVersion)
+	}
+	sum, err := checksum(file.Data)
+	if err != nil {
+		return quarantine(path, err)
+	}
+	if sum != file.Checksum {
+		return quarantine(path, errors.New("checksum mismatch"))
+	}
+	if err := json.Unmarshal(file.Data, v); err != nil {
+		return quarantine(path, err)
+	}
+	return nil
+}
+
+// quarantine renames a corrupt state file to path.corrupt-<timestamp>,
+// warning on stderr, so that it is kept for inspection but no longer read.
+func quarantine(path string, cause error) error {
+	dest := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, dest); err != nil {
+		return fmt.Errorf("%s is corrupt (%v) and cannot be moved aside: %w", path, cause, err)
+	}
+	fmt.Fprintf(os.Stderr, "%s>> Attenzione: %s è danneggiato (%v), spostato in %s%s\n",
+		ColorYellow, filepath.Base(path), cause, filepath.Base(dest), ColorReset)
+	return nil
+}
+
+// saveJSON atomically writes v to path as indented JSON, wrapped in a
+// versioned, checksummed envelope.
+func saveJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	sum, err := checksum(data)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(stateFile{Version: stateVersion, Checksum: sum, Data: data}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(out, '\n'), 0o644)
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadJSON(t *testing.T) {
+	type state struct {
+		Count int `json:"count"`
+	}
+	tests := []struct {
+		name    string
+		content string // empty for a missing file
+		want    int
+		corrupt bool
+		err     string
+	}{
+		{name: "missing", want: -1},
+		{name: "unversioned", content: `{"count": 3}`, want: 3},
+		{name: "truncated", content: `{"count": `, want: -1, corrupt: true},
+		{name: "bad checksum", content: `{"version": 1, "checksum": "sha256:00", "data": {"count": 3}}`, want: -1, corrupt: true},
+		{name: "newer schema", content: `{"version": 99, "checksum": "", "data": {}}`, want: -1, err: "newer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "state.json")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			v := state{Count: -1}
+			err := loadJSON(path, &v)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if v.Count != tt.want {
+				t.Errorf("count %d, want %d", v.Count, tt.want)
+			}
+
+			moved, _ := filepath.Glob(path + ".corrupt-*")
+			if tt.corrupt != (len(moved) == 1) {
+				t.Errorf("quarantined files %q, corrupt %v", moved, tt.corrupt)
+			}
+			if _, err := os.Stat(path); tt.corrupt && !os.IsNotExist(err) {
+				t.Error("corrupt file was not moved aside")
+			}
+		})
+	}
+}
+
+func TestSaveJSONRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	in := map[string]int{"a": 1, "b": 2}
+	if err := saveJSON(path, in); err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]int
+	if err := loadJSON(path, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out["a"] != 1 || out["b"] != 2 {
+		t.Errorf("got %v, want %v", out, in)
+	}
+	if moved, _ := filepath.Glob(path + ".corrupt-*"); len(moved) != 0 {
+		t.Errorf("a valid file was quarantined: %q", moved)
+	}
+}
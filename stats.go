@@ -0,0 +1,140 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sessionStats counts what happened during an interactive session.
+type sessionStats struct {
+	started      time.Time
+	feedsFetched int
+	itemsShown   int
+	itemsOpened  int
+}
+
+// print writes the session summary shown on exit.
+func (s *sessionStats) print(w io.Writer) {
+	fmt.Fprintf(w, "\n%sRiepilogo sessione:%s\n", ColorBold, ColorReset)
+	fmt.Fprintf(w, "  %-18s %d\n", "Feed scaricati:", s.feedsFetched)
+	fmt.Fprintf(w, "  %-18s %d\n", "Notizie mostrate:", s.itemsShown)
+	fmt.Fprintf(w, "  %-18s %d\n", "Notizie aperte:", s.itemsOpened)
+	fmt.Fprintf(w, "  %-18s %s\n", "Durata:", time.Since(s.started).Round(time.Second))
+}
+
+// openedItem is an entry of the log of items opened in the browser.
+type openedItem struct {
+	Title    string    `json:"title"`
+	Link     string    `json:"link"`
+	Category string    `json:"category,omitempty"`
+	Opened   time.Time `json:"opened"`
+}
+
+// openedLogPath returns the path of the opened-items log.
+func openedLogPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "opened.json"), nil
+}
+
+// loadOpened reads the opened-items log.
+func loadOpened() ([]openedItem, error) {
+	path, err := openedLogPath()
+	if err != nil {
+		return nil, err
+	}
+	var items []openedItem
+	if err := loadJSON(path, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// recordOpened appends item to the opened-items log, best effort.
+func recordOpened(item openedItem) {
+	path, err := openedLogPath()
+	if err != nil {
+		return
+	}
+	items, err := loadOpened()
+	if err != nil {
+		return
+	}
+	saveJSON(path, append(items, item))
+}
+
+// runStats implements "adncli stats --opened".
+func (r *RssReader) runStats(args []string) error {
+	plainIfPiped()
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	opened := fs.Bool("opened", false, "riepiloga le notizie aperte per categoria e ora del giorno")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if !*opened {
+		return &usageError{usage: "adncli stats --opened"}
+	}
+
+	items, err := loadOpened()
+	if err != nil {
+		return err
+	}
+
+	w := r.out.content
+	fmt.Fprintf(w, "%sNotizie aperte: %d%s\n", ColorBold, len(items), ColorReset)
+	if len(items) == 0 {
+		return nil
+	}
+
+	byCategory := make(map[string]int)
+	var byHour [24]int
+	for _, it := range items {
+		category := it.Category
+		if category == "" {
+			category = "(sconosciuta)"
+		}
+		byCategory[category]++
+		byHour[it.Opened.Local().Hour()]++
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for c := range byCategory {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if byCategory[categories[i]] != byCategory[categories[j]] {
+			return byCategory[categories[i]] > byCategory[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	fmt.Fprintf(w, "\n%sPer categoria:%s\n", ColorCyan, ColorReset)
+	for _, c := range categories {
+		fmt.Fprintf(w, "  %-20s %4d\n", c, byCategory[c])
+	}
+
+	peak := 0
+	for _, n := range byHour {
+		peak = max(peak, n)
+	}
+	fmt.Fprintf(w, "\n%sPer ora del giorno:%s\n", ColorCyan, ColorReset)
+	for h, n := range byHour {
+		if n == 0 {
+			continue
+		}
+		bar := strings.Repeat("█", max(1, n*30/peak))
+		fmt.Fprintf(w, "  %02d:00 %s %d\n", h, bar, n)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// errOverBudget is the cause of a fetch cut short by --budget, which is
+// not held against the feed: the budget is far shorter than --timeout.
+var errOverBudget = errors.New("tempo della riga di stato esaurito")
+
+// runStatusline prints one uncolored line with the latest headline of a
+// category and its age, for tmux or screen status bars, prefixed with a
+// factor such as "[4x]" when the category publishes at least spikeFactor
+// times its usual volume. The line comes from the feed state; the feed is
+// fetched only when that is older than --max-age, and never for longer
+// than --budget, so that a slow network cannot stall the status bar.
+func (r *RssReader) runStatusline(args []string) error {
+	fs := flag.NewFlagSet("statusline", flag.ContinueOnError)
+	category := fs.String("category", "Ultim'ora", "categoria da mostrare (nome o numero)")
+	budget := fs.Duration("budget", 1500*time.Millisecond, "tempo massimo per aggiornare il feed")
+	maxAge := fs.Duration("max-age", 5*time.Minute, "età dei dati salvati oltre la quale aggiornare il feed")
+	width := fs.Int("width", 60, "lunghezza massima della riga")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cat, ok := r.findCategory(*category)
+	if !ok {
+		return fmt.Errorf("categoria %q sconosciuta", *category)
+	}
+
+	states, err := loadFeedStates()
+	if err != nil {
+		return err
+	}
+	if st := states.Feeds[cat.URL]; st == nil || time.Since(st.LastSuccess) > *maxAge {
+		ctx, cancel := context.WithTimeoutCause(context.Background(), *budget, errOverBudget)
+		_, persist, err := r.fetchUnsaved(ctx, cat.URL)
+		cancel()
+		// The feed state and the archive are written once the line is
+		// out, so that their disk writes do not count against --budget.
+		defer persist()
+		// On failure the saved headline, however old, is better than none.
+		if err == nil {
+			if states, err = loadFeedStates(); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Fprintln(r.out.content, statusLine(cat, states.Feeds[cat.URL], *width))
+	// Closing stdout lets a caller reading the line to the end, such as
+	// tmux or a shell prompt, go on while the fetch is persisted.
+	if f, ok := r.out.content.(*os.File); ok {
+		f.Close()
+	}
+	return nil
+}
+
+// statusLine renders the status line of cat from its feed status st.
+func statusLine(cat FeedCategory, st *feedStatus, width int) string {
+	if st == nil || st.LastHeadline == "" {
+		return truncate(cat.Name+": nessuna notizia", width)
+	}
+
+	published := st.LastPublished
+	if published.IsZero() {
+		published = st.LastSuccess
+	}
+	age := " (" + shortAge(time.Since(published)) + ")"
+	headline := strings.Join(strings.Fields(st.LastHeadline), " ")
+	// A volume spike is flagged up front, where a cut line keeps it.
+	if factor, ok := st.spike(); ok {
+		headline = fmt.Sprintf("[%.0fx] %s", factor, headline)
+	}
+	return truncate(headline, width-len([]rune(age))) + age
+}
+
+// shortAge renders d as a compact age such as "12m", "3h" or "2g".
+func shortAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "ora"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dg", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,433 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// The archive is a bbolt file with two buckets: items maps a sequence
+// number, in the order items were first seen, to the archived item, and
+// keys maps each itemKey to its sequence number, so that an item seen
+// again in a later fetch keeps its first-seen time.
+var (
+	itemsBucket = []byte("items")
+	keysBucket  = []byte("keys")
+)
+
+// archiveLimit is how many items "adncli archive" shows by default.
+const archiveLimit = 50
+
+// archivePruneInterval is how often adncli serve prunes the archive.
+const archivePruneInterval = time.Hour
+
+// archiveLockTimeout is how long to wait for another adncli process to
+// release the archive file, which bbolt locks while it is open.
+const archiveLockTimeout = time.Second
+
+// retention bounds the archive to the items first seen in the last days
+// and to the most recent items of them. Zero fields mean no bound.
+type retention struct {
+	days  int
+	items int
+}
+
+// archiveMu guards archiveDB, which is opened on first use and then kept
+// for the rest of the process, shared by concurrent fetches.
+var (
+	archiveMu sync.Mutex
+	archiveDB *bolt.DB
+)
+
+// archiveWarning reports, once per run, that fetched items could not be
+// archived.
+var archiveWarning sync.Once
+
+// archivePath returns the path of the archive file in the data directory.
+func archivePath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "archive.db"), nil
+}
+
+// openArchive returns the item store, opening it in the data directory
+// and creating it if needed on first use.
+func openArchive() (*bolt.DB, error) {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+	if archiveDB != nil {
+		return archiveDB, nil
+	}
+
+	path, err := archivePath()
+	if err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: archiveLockTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, errors.New("archivio: in uso da un altro processo adncli")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("archivio: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(itemsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(keysBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("archivio: %w", err)
+	}
+	archiveDB = db
+	return db, nil
+}
+
+// closeArchive closes the item store, if open, so that the next use
+// opens it again.
+func closeArchive() error {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+	if archiveDB == nil {
+		return nil
+	}
+	err := archiveDB.Close()
+	archiveDB = nil
+	return err
+}
+
+// archiveItems stores the items of the feed at url that the archive does
+// not have yet. Like recordFetch it is best effort, but the first failure
+// is reported so that a broken archive does not go unnoticed.
+func (r *RssReader) archiveItems(url string, rss *Rss) {
+	if len(rss.Channel.Items) == 0 {
+		return
+	}
+	category := url
+	if cat, ok := r.categoryByURL(url); ok {
+		category = cat.Name
+	}
+	if err := storeItems(category, rss.Channel.Items); err != nil {
+		archiveWarning.Do(func() {
+			fmt.Fprintf(r.out.diag, "%s>> Notizie non archiviate: %v%s\n", ColorYellow, err, ColorReset)
+		})
+	}
+}
+
+// archiveRecord is the stored form of an archived item.
+type archiveRecord struct {
+	Key         string    `json:"key"`
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	Description string    `json:"description"`
+	PubDate     string    `json:"pub_date"`
+	Category    string    `json:"category"`
+	FirstSeen   time.Time `json:"first_seen"`
+}
+
+// storeItems inserts the items of category missing from the archive.
+func storeItems(category string, items []Item) error {
+	db, err := openArchive()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	err = db.Update(func(tx *bolt.Tx) error {
+		records, keys := tx.Bucket(itemsBucket), tx.Bucket(keysBucket)
+		for _, item := range items {
+			key := itemKey(item)
+			if key == "" || keys.Get([]byte(key)) != nil {
+				continue
+			}
+			data, err := json.Marshal(archiveRecord{key, item.Title, item.Link, item.Description, item.PubDate, category, now})
+			if err != nil {
+				return err
+			}
+			n, err := records.NextSequence()
+			if err != nil {
+				return err
+			}
+			seq := binary.BigEndian.AppendUint64(nil, n)
+			if err := records.Put(seq, data); err != nil {
+				return err
+			}
+			if err := keys.Put([]byte(key), seq); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("archivio: %w", err)
+	}
+	return nil
+}
+
+// archiveQuery selects archived items. Empty fields do not filter.
+type archiveQuery struct {
+	categories []string  // category names
+	term       string    // in the title or description, ignoring case
+	since      time.Time // first seen at or after
+	limit      int
+}
+
+// matches reports whether rec passes the filters of q other than since.
+func (q archiveQuery) matches(rec archiveRecord) bool {
+	if len(q.categories) > 0 {
+		found := false
+		for _, name := range q.categories {
+			found = found || name == rec.Category
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.term != "" {
+		term := strings.ToLower(q.term)
+		return strings.Contains(strings.ToLower(rec.Title), term) ||
+			strings.Contains(strings.ToLower(rec.Description), term)
+	}
+	return true
+}
+
+// archivedItem is an item of the archive with the time it was first seen.
+type archivedItem struct {
+	Item
+	firstSeen time.Time
+}
+
+// archivedItems returns the archived items matching q, most recently seen
+// first.
+func archivedItems(q archiveQuery) ([]archivedItem, error) {
+	db, err := openArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []archivedItem
+	err = db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(itemsBucket).Cursor()
+		// Sequence numbers follow the first-seen order, newest last.
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var rec archiveRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			if !q.since.IsZero() && rec.FirstSeen.Before(q.since) {
+				break
+			}
+			if !q.matches(rec) {
+				continue
+			}
+			items = append(items, archivedItem{
+				Item:      Item{Title: rec.Title, Link: rec.Link, Description: rec.Description, PubDate: rec.PubDate, Source: rec.Category},
+				firstSeen: rec.FirstSeen,
+			})
+			if q.limit > 0 && len(items) == q.limit {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archivio: %w", err)
+	}
+	return items, nil
+}
+
+// pruneArchive deletes the items outside ret and returns how many. With
+// vacuum the file is then compacted, giving the space back.
+func pruneArchive(ret retention, vacuum bool) (int64, error) {
+	db, err := openArchive()
+	if err != nil {
+		return 0, err
+	}
+
+	var removed int64
+	err = db.Update(func(tx *bolt.Tx) error {
+		records, keys := tx.Bucket(itemsBucket), tx.Bucket(keysBucket)
+		cutoff := time.Now().UTC().AddDate(0, 0, -ret.days)
+
+		// Collect first: deleting while iterating makes a cursor skip.
+		var stale []archiveRecord
+		var seqs [][]byte
+		kept := 0
+		c := records.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var rec archiveRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			if (ret.days > 0 && rec.FirstSeen.Before(cutoff)) || (ret.items > 0 && kept >= ret.items) {
+				stale = append(stale, rec)
+				seqs = append(seqs, bytes.Clone(k))
+				continue
+			}
+			kept++
+		}
+		for i, rec := range stale {
+			if err := records.Delete(seqs[i]); err != nil {
+				return err
+			}
+			if err := keys.Delete([]byte(rec.Key)); err != nil {
+				return err
+			}
+		}
+		removed = int64(len(stale))
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("archivio: %w", err)
+	}
+	if vacuum {
+		if err := compactArchive(); err != nil {
+			return removed, fmt.Errorf("archivio: %w", err)
+		}
+	}
+	return removed, nil
+}
+
+// compactArchive rewrites the archive into a new file, which bbolt needs
+// to shrink one, and installs it in place of the old.
+func compactArchive() error {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+	if archiveDB == nil {
+		return nil
+	}
+	path := archiveDB.Path()
+	tmp := path + ".compact"
+	os.Remove(tmp)
+	dst, err := bolt.Open(tmp, 0o600, nil)
+	if err != nil {
+		return err
+	}
+	if err := bolt.Compact(dst, archiveDB, 0); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := archiveDB.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	archiveDB = nil
+	// The next use reopens the file, compacted or not.
+	return os.Rename(tmp, path)
+}
+
+// pruneArchiveEvery prunes the archive now and then every interval until
+// ctx is done, for long-running modes. Failures are logged, not fatal.
+func (r *RssReader) pruneArchiveEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if removed, err := pruneArchive(r.retention, false); err != nil {
+			fmt.Fprintf(r.out.diag, ">> Errore nella pulizia dell'archivio: %v\n", err)
+		} else if removed > 0 {
+			fmt.Fprintf(r.out.diag, "Archivio: %d notizie rimosse\n", removed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runArchivePrune implements "adncli archive prune [--vacuum]".
+func (r *RssReader) runArchivePrune(args []string) error {
+	fs := flag.NewFlagSet("archive prune", flag.ContinueOnError)
+	vacuum := fs.Bool("vacuum", false, "compatta il file dell'archivio dopo la pulizia")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return &usageError{usage: "adncli archive prune [--vacuum]"}
+	}
+
+	removed, err := pruneArchive(r.retention, *vacuum)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(r.out.diag, "Notizie rimosse dall'archivio: %d.\n", removed)
+	return nil
+}
+
+// runArchive implements "adncli archive [--category C] [--limit N] [termine]"
+// and "adncli archive prune".
+func (r *RssReader) runArchive(args []string) error {
+	if len(args) > 0 && args[0] == "prune" {
+		return r.runArchivePrune(args[1:])
+	}
+
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	only := fs.String("category", "", "categorie da mostrare, separate da virgola (predefinito: tutte)")
+	limit := fs.Int("limit", archiveLimit, "numero massimo di notizie")
+	words, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if *limit <= 0 {
+		return &usageError{usage: "adncli archive [--category C] [--limit N] [termine]"}
+	}
+
+	var categories []string
+	if *only != "" {
+		for _, key := range strings.Split(*only, ",") {
+			cat, ok := r.findCategory(key)
+			if !ok {
+				return fmt.Errorf("categoria %q sconosciuta", strings.TrimSpace(key))
+			}
+			categories = append(categories, cat.Name)
+		}
+	}
+
+	term := strings.TrimSpace(strings.Join(words, " "))
+	found, err := archivedItems(archiveQuery{categories: categories, term: term, limit: *limit})
+	if err != nil {
+		return err
+	}
+	items := make([]Item, len(found))
+	for i, it := range found {
+		items[i] = it.Item
+	}
+
+	plainIfPiped()
+	if len(items) == 0 {
+		if term != "" {
+			fmt.Fprintf(r.out.content, "Nessuna notizia archiviata per %q.\n", term)
+		} else {
+			fmt.Fprintln(r.out.content, "L'archivio è vuoto.")
+		}
+		return nil
+	}
+
+	r.currentFeed = FeedCategory{Name: "Archivio"}
+	r.displayFeed(&Rss{Channel: Channel{Title: "Archivio", Items: items}})
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestArchive(t *testing.T) {
+	dataHome = t.TempDir()
+	t.Cleanup(func() {
+		closeArchive()
+		dataHome = ""
+	})
+
+	politica := []Item{
+		{GUID: "p1", Title: "Manovra approvata", Link: "https://example.com/p1"},
+		{GUID: "p2", Title: "Crisi di governo", Link: "https://example.com/p2", Description: "Il 50% dei voti"},
+	}
+	sport := []Item{{Title: "Derby", Link: "https://example.com/s1"}}
+	if err := storeItems("Politica", politica); err != nil {
+		t.Fatal(err)
+	}
+	if err := storeItems("Sport", sport); err != nil {
+		t.Fatal(err)
+	}
+	// Items seen again are not duplicated.
+	if err := storeItems("Politica", politica[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query archiveQuery
+		want  []string
+	}{
+		{"all", archiveQuery{}, []string{"Derby", "Crisi di governo", "Manovra approvata"}},
+		{"category", archiveQuery{categories: []string{"Politica"}}, []string{"Crisi di governo", "Manovra approvata"}},
+		{"term in title", archiveQuery{term: "manovra"}, []string{"Manovra approvata"}},
+		{"term in description", archiveQuery{term: "50%"}, []string{"Crisi di governo"}},
+		{"wildcard is literal", archiveQuery{term: "_"}, nil},
+		{"limit", archiveQuery{limit: 1}, []string{"Derby"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := archivedItems(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, it := range items {
+				got = append(got, it.Title)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+
+	removed, err := pruneArchive(retention{days: 90, items: 1}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("pruneArchive removed %d items, want 2", removed)
+	}
+	items, err := archivedItems(archiveQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Title != "Derby" || items[0].Source != "Sport" {
+		t.Errorf("after pruning: %+v", items)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// defaultWidth is used when the terminal size cannot be determined.
+const defaultWidth = 80
+
+// terminalWidth returns the width of stdout, falling back to $COLUMNS and
+// then to defaultWidth.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return defaultWidth
+}
+
+// layoutWidth is the width output is laid out for: the --width override
+// when given, the terminal width otherwise.
+func (r *RssReader) layoutWidth() int {
+	if r.width > 0 {
+		return r.width
+	}
+	return terminalWidth()
+}
+
+// truncate shortens s to at most width runes, ending with an ellipsis when
+// something was cut.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// pad right-pads s with spaces to width runes.
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// displayTable renders the feed as an aligned table of index, time,
+// category and title, with the title column absorbing the leftover width.
+func (r *RssReader) displayTable(rss *Rss) {
+	type row struct{ index, when, category, title string }
+
+	header := row{"#", "Data", "Categoria", "Titolo"}
+	rows := []row{header}
+	for i, item := range rss.Channel.Items {
+		if !r.visible(item) {
+			continue
+		}
+
+		when := ""
+		if t, ok := parsePubDate(item.PubDate); ok {
+			when = dates.short(t)
+		}
+		category := r.currentFeed.Name
+		switch {
+		case item.Source != "":
+			category = item.Source
+		case len(item.Categories) > 0:
+			category = item.Categories[0]
+		}
+		rows = append(rows, row{strconv.Itoa(i + 1), when, category, strings.TrimSpace(item.Title)})
+	}
+
+	var wIndex, wWhen, wCategory int
+	for _, rw := range rows {
+		wIndex = max(wIndex, utf8.RuneCountInString(rw.index))
+		wWhen = max(wWhen, utf8.RuneCountInString(rw.when))
+		wCategory = max(wCategory, utf8.RuneCountInString(rw.category))
+	}
+	wCategory = min(wCategory, 20)
+
+	// Three separators of " | " between four columns.
+	wTitle := max(r.layoutWidth()-wIndex-wWhen-wCategory-9, 10)
+
+	var lines []string
+	longest := 0
+	for _, rw := range rows {
+		line := fmt.Sprintf("%s | %s | %s | %s",
+			pad(rw.index, wIndex),
+			pad(rw.when, wWhen),
+			pad(truncate(rw.category, wCategory), wCategory),
+			truncate(rw.title, wTitle))
+		lines = append(lines, line)
+		longest = max(longest, utf8.RuneCountInString(line))
+	}
+
+	fmt.Fprintf(r.out.content, "\n%s=== %s ===%s\n", ColorBold+ColorGreen, strings.ToUpper(rss.Channel.Title), ColorReset)
+	fmt.Fprintf(r.out.content, "%s%s%s\n", ColorBold, lines[0], ColorReset)
+	fmt.Fprintln(r.out.content, strings.Repeat("-", longest))
+	for _, line := range lines[1:] {
+		fmt.Fprintln(r.out.content, line)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// todoistTasksURL is the Todoist API endpoint for creating tasks.
+const todoistTasksURL = "https://api.todoist.com/api/v1/tasks"
+
+// createTask turns item into a follow-up task. It uses Todoist when
+// TODOIST_API_TOKEN is set and the local Taskwarrior otherwise, and returns
+// the name of the backend used.
+func (r *RssReader) createTask(item *Item) (string, error) {
+	title := strings.TrimSpace(item.Title)
+
+	if token := os.Getenv("TODOIST_API_TOKEN"); token != "" {
+		return "Todoist", r.createTodoistTask(token, title, item.Link)
+	}
+
+	if _, err := exec.LookPath("task"); err != nil {
+		return "", fmt.Errorf("né TODOIST_API_TOKEN né Taskwarrior (task) sono disponibili")
+	}
+	if err := exec.Command("task", "add", "+adncli", "--", title).Run(); err != nil {
+		return "", fmt.Errorf("task add: %w", err)
+	}
+	if item.Link != "" {
+		if err := exec.Command("task", "+LATEST", "annotate", "--", item.Link).Run(); err != nil {
+			return "", fmt.Errorf("task annotate: %w", err)
+		}
+	}
+	return "Taskwarrior", nil
+}
+
+// createTodoistTask creates a Todoist task with the link as description.
+func (r *RssReader) createTodoistTask(token, title, link string) error {
+	payload, err := json.Marshal(map[string]string{
+		"content":     title,
+		"description": link,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, todoistTasksURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.fetcher.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Todoist HTTP error: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"time"
+)
+
+// sshTunnel is a dynamic SOCKS forward opened with the system ssh client.
+type sshTunnel struct {
+	cmd  *exec.Cmd
+	addr string
+}
+
+// startSSHTunnel runs "ssh -D" against dest (user@host) and waits until the
+// local SOCKS port accepts connections.
+func startSSHTunnel(dest string) (*sshTunnel, error) {
+	// Reserve a free local port, then hand it over to ssh.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve a local port: %w", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	cmd := exec.Command("ssh", "-N", "-D", addr,
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+		dest)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ssh: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-exited:
+			return nil, fmt.Errorf("ssh exited: %v", err)
+		default:
+		}
+
+		conn, err := net.DialTimeout("tcp", addr, 200*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return &sshTunnel{cmd: cmd, addr: addr}, nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	cmd.Process.Kill()
+	return nil, fmt.Errorf("timed out waiting for ssh tunnel to %s", dest)
+}
+
+// proxyURL returns the SOCKS5 URL to use as HTTP proxy.
+func (t *sshTunnel) proxyURL() *url.URL {
+	return &url.URL{Scheme: "socks5", Host: t.addr}
+}
+
+// Close terminates the ssh process.
+func (t *sshTunnel) Close() error {
+	return t.cmd.Process.Kill()
+}
+
+// useProxy routes every request made by the reader through proxy.
+func (r *RssReader) useProxy(proxy *url.URL) {
+	transport := r.client.Transport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxy)
+	r.client.Transport = transport
+}
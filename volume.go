@@ -0,0 +1,87 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "time"
+
+// Volume anomalies are a crude breaking-news indicator: a feed publishing
+// far more than usual in the last hour is probably covering something big.
+const (
+	// spikeFactor is how many times the usual hourly volume makes a spike.
+	spikeFactor = 3
+	// spikeMinItems keeps quiet feeds from flagging two items as a spike.
+	spikeMinItems = 5
+	// rateWeight is the weight of a new hourly sample in the moving
+	// average, so that one busy hour barely moves the baseline.
+	rateWeight = 0.2
+)
+
+// publishedSince counts the items of rss published after since, and
+// returns the dates of the newest and oldest dated items.
+func publishedSince(rss *Rss, since time.Time) (n int, newest, oldest time.Time) {
+	for _, item := range rss.Channel.Items {
+		t, ok := parsePubDate(item.PubDate)
+		if !ok {
+			continue
+		}
+		if t.After(since) {
+			n++
+		}
+		if newest.IsZero() || t.After(newest) {
+			newest = t
+		}
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	return n, newest, oldest
+}
+
+// updateVolume records how many items of rss were published in the hour
+// before now and folds that into the usual hourly rate, at most once an
+// hour. The first time, the rate is estimated from the dates in the feed.
+func (st *feedStatus) updateVolume(rss *Rss, now time.Time) {
+	recent, newest, oldest := publishedSince(rss, now.Add(-time.Hour))
+	st.LastHourItems = recent
+
+	if st.RateUpdated.IsZero() {
+		span := newest.Sub(oldest)
+		if span < time.Hour {
+			return
+		}
+		st.HourlyRate = float64(len(rss.Channel.Items)) / span.Hours()
+		st.RateUpdated = now
+		return
+	}
+	if now.Sub(st.RateUpdated) >= time.Hour {
+		st.HourlyRate = (1-rateWeight)*st.HourlyRate + rateWeight*float64(recent)
+		st.RateUpdated = now
+	}
+}
+
+// spike reports how many times the usual rate the last hour's volume is,
+// if that makes an anomaly.
+func (st *feedStatus) spike() (float64, bool) {
+	if st.HourlyRate <= 0 || st.LastHourItems < spikeMinItems {
+		return 0, false
+	}
+	factor := float64(st.LastHourItems) / st.HourlyRate
+	return factor, factor >= spikeFactor
+}
+
+// volumeSpike returns the spike factor of url and its item count in the
+// last hour, if the feed state records an anomaly.
+func volumeSpike(url string) (factor float64, recent int, ok bool) {
+	states, err := loadFeedStates()
+	if err != nil {
+		return 0, 0, false
+	}
+	st, found := states.Feeds[url]
+	if !found {
+		return 0, 0, false
+	}
+	factor, ok = st.spike()
+	return factor, st.LastHourItems, ok
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Ivan Guerreschi. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// explainItem shows why an item of the feed shown last is displayed the
+// way it is: where it comes from, which filters it passes and what each
+// cleaning stage did to its description.
+func (r *RssReader) explainItem(arg string) error {
+	item, err := r.itemAt(arg)
+	if err != nil {
+		return err
+	}
+
+	field := func(name, value string) {
+		fmt.Fprintf(r.out.content, "%s%s:%s %s\n", ColorCyan, name, ColorReset, value)
+	}
+
+	fmt.Fprintf(r.out.content, "\n%sNotizia %s: %s%s\n", ColorBold, arg, strings.TrimSpace(item.Title), ColorReset)
+	source := r.sourceOf(*item)
+	field("Fonte", fmt.Sprintf("%s (%s)", source.Name, source.URL))
+
+	tags := "nessuno"
+	if len(item.Categories) > 0 {
+		tags = strings.Join(item.Categories, ", ")
+	}
+	switch {
+	case r.tagFilter == "":
+		field("Filtro tag", fmt.Sprintf("nessuno attivo (tag della notizia: %s)", tags))
+	case r.visible(*item):
+		field("Filtro tag", fmt.Sprintf("%q superato (tag della notizia: %s)", r.tagFilter, tags))
+	default:
+		field("Filtro tag", fmt.Sprintf("%q non superato, notizia nascosta (tag della notizia: %s)", r.tagFilter, tags))
+	}
+
+	if r.hideDesc {
+		field("Descrizione", "nascosta (comando d o --no-desc)")
+	} else {
+		field("Descrizione", "mostrata")
+	}
+
+	if item.Source != "" {
+		field("Duplicati", "link ripetuti in più categorie mostrati una volta sola, sotto la prima")
+	} else {
+		field("Duplicati", "nessun controllo in una singola categoria; la vista Tutte le notizie e --briefing scartano i link ripetuti")
+	}
+
+	// The change is net: a stage such as alt can add text as well as drop it.
+	fmt.Fprintf(r.out.content, "%sPulizia della descrizione (variazione netta):%s\n", ColorCyan, ColorReset)
+	text := item.Description
+	for _, stage := range r.pipelineFor(source) {
+		cleaned := stage.apply(text)
+		delta := utf8.RuneCountInString(cleaned) - utf8.RuneCountInString(text)
+		switch {
+		case cleaned == text:
+			fmt.Fprintf(r.out.content, "  %-12s nessuna modifica\n", stage.name)
+		case delta < 0:
+			fmt.Fprintf(r.out.content, "  %-12s %d caratteri in meno\n", stage.name, -delta)
+		case delta > 0:
+			fmt.Fprintf(r.out.content, "  %-12s %d caratteri in più\n", stage.name, delta)
+		default:
+			fmt.Fprintf(r.out.content, "  %-12s testo modificato, stessa lunghezza\n", stage.name)
+		}
+		text = cleaned
+	}
+	if len(r.pipelineFor(source)) == 0 {
+		fmt.Fprintln(r.out.content, "  nessuna fase attiva (--clean vuoto)")
+	}
+	return nil
+}